@@ -16,6 +16,8 @@ package main
 
 import (
 	"flag"
+	"log"
+	"os"
 	"runtime"
 	"time"
 
@@ -23,11 +25,14 @@ import (
 )
 
 var (
-	endpoint = flag.String("endpoint", ":8181", "Endpoint to serve metrics via HTTP.")
-	interval = flag.Duration("interval", time.Second, "Polling interval for temperature information")
-	logFile  = flag.String("log", "", "ndjson (newline delimited json) log file")
-	console  = flag.Bool("console", true, "Indicates that records should be printed to console.")
-	svc      *string
+	endpoint    = flag.String("endpoint", ":8181", "Endpoint to serve metrics via HTTP.")
+	interval    = flag.Duration("interval", time.Second, "Polling interval for temperature information")
+	logFile     = flag.String("log", "", "ndjson (newline delimited json) log file")
+	console     = flag.Bool("console", true, "Indicates that records should be printed to console.")
+	config      = flag.String("config", "", "YAML config file to rename, ignore and calibrate sensors and to set up the modules of /probe")
+	driver      = flag.String("driver", "", "Comma separated list of drivers to read sensors from, like 'hwmon,lmsensors' or 'replay?file=cputemps.ndjson&loop=true'. The default picks one for this platform, -list-drivers shows the drivers and their options.")
+	listDrivers = flag.Bool("list-drivers", false, "Print the drivers that are available on this machine and exit.")
+	svc         *string
 )
 
 func init() {
@@ -39,6 +44,13 @@ func init() {
 func main() {
 	flag.Parse()
 
+	if *listDrivers {
+		if err := internal.ListDrivers(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	svcCmd := ""
 	if svc != nil {
 		svcCmd = *svc
@@ -49,6 +61,8 @@ func main() {
 		Interval:              *interval,
 		Log:                   *logFile,
 		Console:               *console,
+		Config:                *config,
+		Driver:                *driver,
 		ServiceControlCommand: svcCmd,
 	})
 }
@@ -16,6 +16,7 @@
 package common
 
 import (
+	"io"
 	"os"
 
 	pb "github.com/jeremyje/coretemp-exporter/proto"
@@ -39,6 +40,14 @@ func (n *notSupportedDriver) Get() (*pb.MachineMetrics, error) {
 	return nil, n.err
 }
 
+// Close releases the resources of d, like the connection of a remote driver, if it has any.
+func Close(d Driver) error {
+	if c, ok := d.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 func Hostname() string {
 	name, err := os.Hostname()
 	if err != nil {
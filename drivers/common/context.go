@@ -0,0 +1,90 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+// ContextDriver is a Driver that gives up when its context is cancelled or reaches its deadline.
+type ContextDriver interface {
+	Driver
+	GetContext(ctx context.Context) (*pb.MachineMetrics, error)
+}
+
+// WithContext returns d if it is already a ContextDriver.
+// Other drivers are called in the background so GetContext returns when ctx is done even if Get() never does.
+// Until an abandoned call returns, later calls fail right away instead of piling up behind it.
+func WithContext(d Driver) ContextDriver {
+	if cd, ok := d.(ContextDriver); ok {
+		return cd
+	}
+	return &contextDriver{
+		driver: d,
+	}
+}
+
+// IsTimeout returns true if err is, or wraps, a context deadline.
+func IsTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+type contextDriver struct {
+	driver Driver
+
+	mu   sync.Mutex
+	busy bool
+}
+
+type getResult struct {
+	mm  *pb.MachineMetrics
+	err error
+}
+
+func (d *contextDriver) Get() (*pb.MachineMetrics, error) {
+	return d.driver.Get()
+}
+
+func (d *contextDriver) GetContext(ctx context.Context) (*pb.MachineMetrics, error) {
+	d.mu.Lock()
+	if d.busy {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("cannot get metrics, an earlier call that timed out has not returned yet, err= %w", context.DeadlineExceeded)
+	}
+	d.busy = true
+	d.mu.Unlock()
+
+	// The channel is buffered so an abandoned call does not block once it returns.
+	done := make(chan *getResult, 1)
+	go func() {
+		mm, err := d.driver.Get()
+		d.mu.Lock()
+		d.busy = false
+		d.mu.Unlock()
+		done <- &getResult{mm: mm, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.mm, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("cannot get metrics, err= %w", ctx.Err())
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+// blockingDriver returns once release is closed.
+type blockingDriver struct {
+	release chan struct{}
+}
+
+func (d *blockingDriver) Get() (*pb.MachineMetrics, error) {
+	<-d.release
+	return &pb.MachineMetrics{Name: "blocking"}, nil
+}
+
+type contextAwareDriver struct {
+	blockingDriver
+}
+
+func (d *contextAwareDriver) GetContext(ctx context.Context) (*pb.MachineMetrics, error) {
+	return nil, ctx.Err()
+}
+
+func TestWithContext(t *testing.T) {
+	d := &blockingDriver{release: make(chan struct{})}
+	cd := WithContext(d)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := cd.GetContext(ctx); !IsTimeout(err) {
+		t.Fatalf("GetContext() error = %v, want a timeout", err)
+	}
+
+	// The first call is still running so the next one fails right away.
+	if _, err := cd.GetContext(context.Background()); !IsTimeout(err) {
+		t.Fatalf("GetContext() error = %v, want a timeout", err)
+	}
+
+	close(d.release)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mm, err := cd.GetContext(context.Background())
+		if err == nil {
+			if mm.GetName() != "blocking" {
+				t.Errorf("name = '%s', want 'blocking'", mm.GetName())
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("GetContext() did not recover after the driver returned, err= %s", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWithContextCancel(t *testing.T) {
+	d := &blockingDriver{release: make(chan struct{})}
+	defer close(d.release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := WithContext(d).GetContext(ctx)
+	if !errors.Is(err, context.Canceled) || IsTimeout(err) {
+		t.Errorf("GetContext() error = %v, want a cancellation", err)
+	}
+}
+
+func TestWithContextPassthrough(t *testing.T) {
+	d := &contextAwareDriver{}
+	if cd := WithContext(d); cd != d {
+		t.Errorf("WithContext() should return a ContextDriver as is, got %T", cd)
+	}
+}
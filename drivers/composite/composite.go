@@ -0,0 +1,172 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package composite combines several drivers into one.
+package composite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Error is returned when some of the drivers fail. The devices of the drivers that succeeded are still returned with it.
+type Error struct {
+	// Errs are the errors of the drivers that failed, in the order the drivers were given.
+	Errs []error
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of the drivers failed: %s", len(e.Errs), strings.Join(msgs, "; "))
+}
+
+// Is returns true if any of the errors is target, so a timeout of one driver can be told apart with errors.Is.
+func (e *Error) Is(target error) bool {
+	for _, err := range e.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// New creates a driver that calls every driver concurrently and merges their devices, in the order of the drivers, into one MachineMetrics.
+// Drivers that read the same sensors, like hwmon and lmsensors, report the same devices. A device is only kept from the first driver that reports it.
+// When the context is done the devices of the drivers that have already returned are kept and the others fail.
+func New(drivers ...common.Driver) common.Driver {
+	d := &compositeDriver{
+		drivers: make([]common.ContextDriver, len(drivers)),
+		closers: drivers,
+	}
+	for i, driver := range drivers {
+		d.drivers[i] = common.WithContext(driver)
+	}
+	return d
+}
+
+type compositeDriver struct {
+	drivers []common.ContextDriver
+	// closers are the drivers as they were given, WithContext hides their Close method.
+	closers []common.Driver
+}
+
+type result struct {
+	mm  *pb.MachineMetrics
+	err error
+}
+
+func (d *compositeDriver) Get() (*pb.MachineMetrics, error) {
+	return d.GetContext(context.Background())
+}
+
+// Close closes every driver that has resources to release, it returns an Error with the drivers that could not be closed.
+func (d *compositeDriver) Close() error {
+	errs := []error{}
+	for _, driver := range d.closers {
+		if err := common.Close(driver); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &Error{
+			Errs: errs,
+		}
+	}
+	return nil
+}
+
+func (d *compositeDriver) GetContext(ctx context.Context) (*pb.MachineMetrics, error) {
+	timestamp := timestamppb.Now()
+	results := make([]*result, len(d.drivers))
+
+	var wg sync.WaitGroup
+	for i, driver := range d.drivers {
+		wg.Add(1)
+		go func(i int, driver common.ContextDriver) {
+			defer wg.Done()
+			mm, err := driver.GetContext(ctx)
+			results[i] = &result{
+				mm:  mm,
+				err: err,
+			}
+		}(i, driver)
+	}
+	wg.Wait()
+
+	mm := &pb.MachineMetrics{
+		Name:      common.Hostname(),
+		Timestamp: timestamp,
+		Device:    []*pb.DeviceMetrics{},
+	}
+	errs := []error{}
+	succeeded := false
+	seen := map[string]bool{}
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+		if r.mm == nil {
+			continue
+		}
+		succeeded = true
+		keys := map[string]bool{}
+		for _, device := range r.mm.GetDevice() {
+			key := deviceKey(device)
+			if seen[key] {
+				continue
+			}
+			keys[key] = true
+			mm.Device = append(mm.Device, device)
+		}
+		for key := range keys {
+			seen[key] = true
+		}
+	}
+
+	if len(errs) == 0 {
+		return mm, nil
+	}
+	err := &Error{
+		Errs: errs,
+	}
+	if !succeeded {
+		return nil, err
+	}
+	return mm, err
+}
+
+// deviceKey identifies a device by the labels of its metrics, two devices with the same key would overwrite each other.
+func deviceKey(device *pb.DeviceMetrics) string {
+	return strings.Join([]string{
+		device.GetKind(),
+		device.GetName(),
+		device.GetChip(),
+		fmt.Sprint(device.GetCpu().GetSocket()),
+		device.GetFan().GetLabel(),
+		device.GetVoltage().GetLabel(),
+		device.GetStorage().GetDevice(),
+		device.GetThermalZone().GetZone(),
+		device.GetCoolingDevice().GetDevice(),
+	}, "\x00")
+}
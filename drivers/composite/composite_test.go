@@ -0,0 +1,228 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package composite
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type fakeDriver struct {
+	devices []*pb.DeviceMetrics
+	err     error
+	started *sync.WaitGroup
+}
+
+func (d *fakeDriver) Get() (*pb.MachineMetrics, error) {
+	if d.started != nil {
+		// Wait for every driver to start to prove that they are called concurrently.
+		d.started.Done()
+		d.started.Wait()
+	}
+	if d.devices == nil {
+		return nil, d.err
+	}
+	return &pb.MachineMetrics{
+		Name:      "child-hostname",
+		Timestamp: timestamppb.New(time.Unix(1, 0)),
+		Device:    d.devices,
+	}, d.err
+}
+
+func TestGet(t *testing.T) {
+	errFake := errors.New("fake")
+	cpu := &pb.DeviceMetrics{Name: "cpu", Kind: "cpu"}
+	fan := &pb.DeviceMetrics{Name: "fan", Kind: "fan"}
+	storage := &pb.DeviceMetrics{Name: "storage", Kind: "storage"}
+	otherCPU := &pb.DeviceMetrics{Name: "cpu", Kind: "cpu", Cpu: &pb.CpuDeviceMetrics{Socket: 1}}
+	fan0 := &pb.DeviceMetrics{Name: "nct6775-isa-0290", Kind: "fan", Chip: "nct6775-isa-0290", Fan: &pb.FanDeviceMetrics{Label: "fan0", Rpm: 1000}}
+	fan0Again := &pb.DeviceMetrics{Name: "nct6775-isa-0290", Kind: "fan", Chip: "nct6775-isa-0290", Fan: &pb.FanDeviceMetrics{Label: "fan0", Rpm: 1010}}
+	fan1 := &pb.DeviceMetrics{Name: "nct6775-isa-0290", Kind: "fan", Chip: "nct6775-isa-0290", Fan: &pb.FanDeviceMetrics{Label: "fan1", Rpm: 900}}
+
+	tests := []struct {
+		name     string
+		drivers  []common.Driver
+		want     []*pb.DeviceMetrics
+		wantErrs []error
+	}{
+		{
+			name:    "no drivers",
+			drivers: []common.Driver{},
+			want:    []*pb.DeviceMetrics{},
+		},
+		{
+			name: "merge in order",
+			drivers: []common.Driver{
+				&fakeDriver{devices: []*pb.DeviceMetrics{cpu, fan}},
+				&fakeDriver{devices: []*pb.DeviceMetrics{storage}},
+			},
+			want: []*pb.DeviceMetrics{cpu, fan, storage},
+		},
+		{
+			name: "devices read by two drivers are kept once",
+			drivers: []common.Driver{
+				&fakeDriver{devices: []*pb.DeviceMetrics{cpu, fan, fan0}},
+				&fakeDriver{devices: []*pb.DeviceMetrics{otherCPU, fan1, fan0Again, storage}},
+			},
+			want: []*pb.DeviceMetrics{cpu, fan, fan0, otherCPU, fan1, storage},
+		},
+		{
+			name: "partial failure",
+			drivers: []common.Driver{
+				&fakeDriver{err: errFake},
+				&fakeDriver{devices: []*pb.DeviceMetrics{storage}},
+			},
+			want:     []*pb.DeviceMetrics{storage},
+			wantErrs: []error{errFake},
+		},
+		{
+			name: "driver with devices and an error",
+			drivers: []common.Driver{
+				&fakeDriver{devices: []*pb.DeviceMetrics{cpu}, err: errFake},
+				&fakeDriver{devices: []*pb.DeviceMetrics{fan}},
+			},
+			want:     []*pb.DeviceMetrics{cpu, fan},
+			wantErrs: []error{errFake},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := New(tc.drivers...).Get()
+			if tc.wantErrs == nil && err != nil {
+				t.Fatal(err)
+			}
+			if tc.wantErrs != nil {
+				var compositeErr *Error
+				if !errors.As(err, &compositeErr) {
+					t.Fatalf("expected a composite error, got %v", err)
+				}
+				if diff := cmp.Diff(tc.wantErrs, compositeErr.Errs, cmp.Comparer(func(a, b error) bool { return a == b })); diff != "" {
+					t.Errorf("Errs mismatch (-want +got):\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got.GetDevice(), protocmp.Transform()); diff != "" {
+				t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+			}
+			if got.GetName() != common.Hostname() {
+				t.Errorf("expected hostname %s, got %s", common.Hostname(), got.GetName())
+			}
+			if got.GetTimestamp().AsTime().Before(time.Unix(2, 0)) {
+				t.Errorf("expected the timestamp of the composite driver, got %s", got.GetTimestamp().AsTime())
+			}
+		})
+	}
+}
+
+func TestGetAllFailed(t *testing.T) {
+	errFake := errors.New("fake")
+	errOther := errors.New("other")
+	got, err := New(&fakeDriver{err: errFake}, &fakeDriver{err: errOther}).Get()
+	if got != nil {
+		t.Errorf("MachineMetrics should be nil, got %v", got)
+	}
+	if err == nil || err.Error() != "2 of the drivers failed: fake; other" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestGetContextTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	d := New(
+		&fakeDriver{devices: []*pb.DeviceMetrics{{Name: "a"}}},
+		&hungDriver{release: release},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	got, err := common.WithContext(d).GetContext(ctx)
+	if !common.IsTimeout(err) {
+		t.Errorf("GetContext() error = %v, want a timeout", err)
+	}
+	if diff := cmp.Diff([]*pb.DeviceMetrics{{Name: "a"}}, got.GetDevice(), protocmp.Transform()); diff != "" {
+		t.Errorf("GetContext() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+type hungDriver struct {
+	release chan struct{}
+}
+
+func (d *hungDriver) Get() (*pb.MachineMetrics, error) {
+	<-d.release
+	return nil, nil
+}
+
+func TestGetConcurrent(t *testing.T) {
+	started := &sync.WaitGroup{}
+	started.Add(3)
+	d := New(
+		&fakeDriver{devices: []*pb.DeviceMetrics{{Name: "a"}}, started: started},
+		&fakeDriver{devices: []*pb.DeviceMetrics{{Name: "b"}}, started: started},
+		&fakeDriver{devices: []*pb.DeviceMetrics{{Name: "c"}}, started: started},
+	)
+	done := make(chan *pb.MachineMetrics)
+	go func() {
+		mm, _ := d.Get()
+		done <- mm
+	}()
+	select {
+	case mm := <-done:
+		if len(mm.GetDevice()) != 3 {
+			t.Errorf("expected 3 devices, got %v", mm.GetDevice())
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("drivers were not called concurrently")
+	}
+}
+
+type closingDriver struct {
+	fakeDriver
+	closed bool
+	err    error
+}
+
+func (d *closingDriver) Close() error {
+	d.closed = true
+	return d.err
+}
+
+func TestClose(t *testing.T) {
+	errFake := errors.New("fake")
+	first := &closingDriver{}
+	second := &closingDriver{err: errFake}
+	d := New(first, &fakeDriver{}, second)
+
+	err := common.Close(d)
+	if !errors.Is(err, errFake) {
+		t.Errorf("Close() = %v, want %v", err, errFake)
+	}
+	if !first.closed || !second.closed {
+		t.Error("Close() should close every driver that can be closed")
+	}
+}
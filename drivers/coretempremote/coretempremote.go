@@ -0,0 +1,285 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package coretempremote reads Core Temp on another machine through its Remote Server plugin.
+//
+// The plugin streams the shared data of Core Temp as JSON objects over TCP, with the same field names as the SDK:
+//
+//	{"CpuInfo":{"uiLoad":[12,3],"uiTjMax":[100],"uiCoreCnt":2,"uiCPUCnt":1,"fTemp":[48,46],"fVID":1.18,"fCPUSpeed":4200,"fFSBSpeed":100,"fMultiplier":42,"CPUName":"Intel(R) Core(TM) i7-8700K CPU @ 3.70GHz","ucFahrenheit":false,"ucDeltaToTjMax":false}}
+package coretempremote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	"github.com/jeremyje/coretemp-exporter/drivers/coretempsdk"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultPort is the port the Remote Server plugin listens on.
+	DefaultPort = "5200"
+)
+
+// Options controls the connection to the Remote Server.
+type Options struct {
+	// Name is the name of the remote machine in the metrics, it defaults to the host of the address.
+	Name string
+	// Timeout is how long Get() waits for the first message, it defaults to 5s.
+	Timeout time.Duration
+	// MaxAge is how old the last message can be before Get() fails, it defaults to 10s.
+	// The connection is also dropped and made again if the server is silent for that long.
+	MaxAge time.Duration
+	// RetryInterval is how long to wait before connecting again after the connection fails, it defaults to 1s.
+	RetryInterval time.Duration
+}
+
+// New creates a driver that reads Core Temp from the Remote Server at address, like "gaming-pc:5200".
+// The driver connects in the background on the first call to Get() and connects again whenever the connection drops.
+func New(address string, opts Options) common.Driver {
+	return newDriver(address, opts)
+}
+
+func newDriver(address string, opts Options) *remoteDriver {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+		port = DefaultPort
+	}
+	if opts.Name == "" {
+		opts.Name = host
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = 10 * time.Second
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = time.Second
+	}
+	return &remoteDriver{
+		address: net.JoinHostPort(host, port),
+		opts:    opts,
+		ready:   make(chan struct{}),
+		done:    make(chan struct{}),
+		energy:  coretempsdk.NewEnergyMeter(),
+	}
+}
+
+type remoteDriver struct {
+	address string
+	opts    Options
+
+	start sync.Once
+	// ready is closed once the first message arrives.
+	ready chan struct{}
+	// done is closed by Close() to stop the background connection.
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu      sync.Mutex
+	conn    net.Conn
+	latest  *coretempsdk.SharedData
+	at      time.Time
+	lastErr error
+	energy  *coretempsdk.EnergyMeter
+}
+
+func (d *remoteDriver) Get() (*pb.MachineMetrics, error) {
+	return d.GetContext(context.Background())
+}
+
+func (d *remoteDriver) GetContext(ctx context.Context) (*pb.MachineMetrics, error) {
+	d.start.Do(func() {
+		go d.run()
+	})
+
+	timer := time.NewTimer(d.opts.Timeout)
+	defer timer.Stop()
+	select {
+	case <-d.ready:
+	case <-timer.C:
+		return nil, fmt.Errorf("cannot read Core Temp Remote Server '%s', no data after %s, err= %v", d.address, d.opts.Timeout, d.err())
+	case <-ctx.Done():
+		return nil, fmt.Errorf("cannot read Core Temp Remote Server '%s', err= %w", d.address, ctx.Err())
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if age := time.Since(d.at); age > d.opts.MaxAge {
+		return nil, fmt.Errorf("cannot read Core Temp Remote Server '%s', the last data is %s old, err= %v", d.address, age.Round(time.Second), d.lastErr)
+	}
+	mm := coretempsdk.ToMachineMetrics(d.latest)
+	mm.Name = d.opts.Name
+	mm.Timestamp = timestamppb.New(d.at)
+	d.energy.Update(mm)
+	return mm, nil
+}
+
+// Close stops the background connection.
+func (d *remoteDriver) Close() error {
+	d.closeOnce.Do(func() {
+		close(d.done)
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.conn != nil {
+			d.conn.Close()
+		}
+	})
+	return nil
+}
+
+func (d *remoteDriver) err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastErr
+}
+
+// run keeps a connection to the server until Close() is called.
+func (d *remoteDriver) run() {
+	for {
+		if err := d.connect(); err != nil {
+			d.mu.Lock()
+			d.lastErr = err
+			d.mu.Unlock()
+		}
+		select {
+		case <-d.done:
+			return
+		case <-time.After(d.opts.RetryInterval):
+		}
+	}
+}
+
+// connect reads messages until the connection fails.
+func (d *remoteDriver) connect() error {
+	conn, err := net.DialTimeout("tcp", d.address, d.opts.Timeout)
+	if err != nil {
+		return fmt.Errorf("cannot connect to '%s', err= %w", d.address, err)
+	}
+	defer conn.Close()
+
+	d.mu.Lock()
+	select {
+	case <-d.done:
+		d.mu.Unlock()
+		return nil
+	default:
+	}
+	d.conn = conn
+	d.mu.Unlock()
+
+	decoder := json.NewDecoder(conn)
+	for {
+		// A server that stops sending is treated like a dropped connection.
+		if err := conn.SetReadDeadline(time.Now().Add(d.opts.MaxAge)); err != nil {
+			return fmt.Errorf("cannot read from '%s', err= %w", d.address, err)
+		}
+		msg := &remoteMessage{}
+		if err := decoder.Decode(msg); err != nil {
+			return fmt.Errorf("cannot read from '%s', err= %w", d.address, err)
+		}
+
+		d.mu.Lock()
+		first := d.latest == nil
+		d.latest = msg.CPUInfo.sharedData()
+		d.at = time.Now()
+		d.lastErr = nil
+		d.mu.Unlock()
+		if first {
+			close(d.ready)
+		}
+	}
+}
+
+// remoteMessage is one message of the Remote Server plugin, only the CPU information is used.
+type remoteMessage struct {
+	CPUInfo remoteCPUInfo `json:"CpuInfo"`
+}
+
+type remoteCPUInfo struct {
+	Load           []uint32  `json:"uiLoad"`
+	TjMax          []uint32  `json:"uiTjMax"`
+	CoreCnt        uint32    `json:"uiCoreCnt"`
+	CPUCnt         uint32    `json:"uiCPUCnt"`
+	Temp           []float32 `json:"fTemp"`
+	VID            float32   `json:"fVID"`
+	CPUSpeed       float32   `json:"fCPUSpeed"`
+	FSBSpeed       float32   `json:"fFSBSpeed"`
+	Multiplier     float32   `json:"fMultiplier"`
+	CPUName        string    `json:"CPUName"`
+	Fahrenheit     flag      `json:"ucFahrenheit"`
+	DeltaToTjMax   flag      `json:"ucDeltaToTjMax"`
+	TdpSupported   flag      `json:"ucTdpSupported"`
+	PowerSupported flag      `json:"ucPowerSupported"`
+	StructVersion  uint32    `json:"uiStructVersion"`
+	Tdp            []uint32  `json:"uiTdp"`
+	Power          []float32 `json:"fPower"`
+	Multipliers    []float32 `json:"fMultipliers"`
+}
+
+// sharedData converts the message to the struct of the SDK so it is mapped the same way as the coretempsdk driver.
+func (c *remoteCPUInfo) sharedData() *coretempsdk.SharedData {
+	data := &coretempsdk.SharedData{
+		CoreCnt:        c.CoreCnt,
+		CPUCnt:         c.CPUCnt,
+		VID:            c.VID,
+		CPUSpeed:       c.CPUSpeed,
+		FSBSpeed:       c.FSBSpeed,
+		Multiplier:     c.Multiplier,
+		Fahrenheit:     byte(c.Fahrenheit),
+		DeltaToTjMax:   byte(c.DeltaToTjMax),
+		TdpSupported:   byte(c.TdpSupported),
+		PowerSupported: byte(c.PowerSupported),
+		StructVersion:  c.StructVersion,
+	}
+	copy(data.Load[:], c.Load)
+	copy(data.TjMax[:], c.TjMax)
+	copy(data.Temp[:], c.Temp)
+	copy(data.CPUName[:], c.CPUName)
+	copy(data.Tdp[:], c.Tdp)
+	copy(data.Power[:], c.Power)
+	copy(data.Multipliers[:], c.Multipliers)
+	return data
+}
+
+// flag is a byte flag of the shared data, some versions of the plugin send it as a number and others as a boolean.
+type flag byte
+
+func (f *flag) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch string(data) {
+	case "true":
+		*f = 1
+		return nil
+	case "false", "null":
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseUint(string(data), 10, 8)
+	if err != nil {
+		return fmt.Errorf("cannot parse flag '%s', it is neither a boolean nor a number, err= %w", data, err)
+	}
+	*f = flag(v)
+	return nil
+}
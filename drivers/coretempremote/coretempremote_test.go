@@ -0,0 +1,258 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coretempremote
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+var (
+	//go:embed testdata/remote.json
+	remoteJSON []byte
+)
+
+// fakeServer is a Remote Server that sends the messages on conns to each connection, one connection after the other.
+type fakeServer struct {
+	lis net.Listener
+}
+
+func newFakeServer(t *testing.T, conns ...[][]byte) *fakeServer {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for _, messages := range conns {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			for _, msg := range messages {
+				if _, err := conn.Write(msg); err != nil {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			// Hang up after the last message so the driver has to connect again.
+			conn.Close()
+		}
+	}()
+	return &fakeServer{lis: lis}
+}
+
+func (s *fakeServer) address() string {
+	return s.lis.Addr().String()
+}
+
+func withTemperature(t *testing.T, temp float32) []byte {
+	t.Helper()
+	msg := &remoteMessage{}
+	if err := json.Unmarshal(remoteJSON, msg); err != nil {
+		t.Fatal(err)
+	}
+	msg.CPUInfo.Temp[0] = temp
+	data, err := json.Marshal(map[string]any{
+		"CpuInfo": map[string]any{
+			"uiLoad":    msg.CPUInfo.Load,
+			"uiTjMax":   msg.CPUInfo.TjMax,
+			"uiCoreCnt": msg.CPUInfo.CoreCnt,
+			"uiCPUCnt":  msg.CPUInfo.CPUCnt,
+			"fTemp":     msg.CPUInfo.Temp,
+			"CPUName":   msg.CPUInfo.CPUName,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func newTestDriver(address string) *remoteDriver {
+	return newDriver(address, Options{
+		Name:          "gaming-pc",
+		Timeout:       5 * time.Second,
+		RetryInterval: 10 * time.Millisecond,
+	})
+}
+
+func TestGet(t *testing.T) {
+	server := newFakeServer(t, [][]byte{remoteJSON, remoteJSON})
+	d := newTestDriver(server.address())
+	defer d.Close()
+
+	got, err := d.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pb.MachineMetrics{
+		Name: "gaming-pc",
+		Device: []*pb.DeviceMetrics{
+			{
+				Name:        "Intel(R) Core(TM) i7-8700K CPU @ 3.70GHz",
+				Kind:        "cpu",
+				Temperature: 51.75,
+				Cpu: &pb.CpuDeviceMetrics{
+					Load:               []int32{12, 3, 45, 100},
+					Temperature:        []float64{48, 46, 52, 61},
+					NumCores:           4,
+					FrequencyMhz:       4200,
+					FsbFrequencyMhz:    100,
+					PackageTemperature: 61,
+					TemperatureMax:     []float64{100, 100, 100, 100},
+					TemperatureCrit:    []float64{100, 100, 100, 100},
+					TemperatureAlarm:   []bool{false, false, false, false},
+					PackagePowerWatts:  64.5,
+					TdpWatts:           95,
+					Multiplier:         42,
+					CoreMultiplier:     []float64{42, 42, 43, 44},
+					CoreFrequencyHz:    []float64{4200000000, 4200000000, 4300000000, 4400000000},
+				},
+			},
+			{
+				Name: "Intel(R) Core(TM) i7-8700K CPU @ 3.70GHz",
+				Kind: "voltage",
+				Voltage: &pb.VoltageDeviceMetrics{
+					Label: "VID",
+					Volts: 1.1875,
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform(), protocmp.IgnoreFields(&pb.MachineMetrics{}, "timestamp")); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetReconnects(t *testing.T) {
+	// The first connection drops after one message, the data of the second connection must show up.
+	server := newFakeServer(t, [][]byte{withTemperature(t, 30)}, [][]byte{withTemperature(t, 70), withTemperature(t, 70), withTemperature(t, 70)})
+	d := newTestDriver(server.address())
+	defer d.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mm, err := d.Get()
+		if err == nil && mm.GetDevice()[0].GetCpu().GetTemperature()[0] == 70 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the driver did not reconnect, got %v, err= %v", mm, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGetStale(t *testing.T) {
+	server := newFakeServer(t, [][]byte{remoteJSON})
+	d := newDriver(server.address(), Options{
+		MaxAge:        50 * time.Millisecond,
+		RetryInterval: time.Hour,
+	})
+	defer d.Close()
+
+	if _, err := d.Get(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err := d.Get(); err == nil || !strings.Contains(err.Error(), "old") {
+		t.Errorf("Get() error = %v, want stale data", err)
+	}
+}
+
+func TestGetContextNoServer(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := lis.Addr().String()
+	lis.Close()
+
+	d := newTestDriver(address)
+	defer d.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := d.GetContext(ctx); !common.IsTimeout(err) {
+		t.Errorf("GetContext() error = %v, want a timeout", err)
+	}
+}
+
+func TestNewAddress(t *testing.T) {
+	tests := []struct {
+		input       string
+		wantAddress string
+		wantName    string
+	}{
+		{input: "gaming-pc", wantAddress: "gaming-pc:5200", wantName: "gaming-pc"},
+		{input: "gaming-pc:1234", wantAddress: "gaming-pc:1234", wantName: "gaming-pc"},
+		{input: "[::1]:1234", wantAddress: "[::1]:1234", wantName: "::1"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			d := newDriver(tc.input, Options{})
+			if d.address != tc.wantAddress || d.opts.Name != tc.wantName {
+				t.Errorf("newDriver(%s) = (%s, %s), want (%s, %s)", tc.input, d.address, d.opts.Name, tc.wantAddress, tc.wantName)
+			}
+		})
+	}
+}
+
+func TestFlag(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    flag
+		wantErr bool
+	}{
+		{input: "true", want: 1},
+		{input: "false", want: 0},
+		{input: "1", want: 1},
+		{input: "0", want: 0},
+		{input: `"yes"`, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			var got flag
+			err := json.NewDecoder(bytes.NewReader([]byte(tc.input))).Decode(&got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Decode() error = %v, want error %t", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Decode() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
@@ -21,3 +21,8 @@ import "github.com/jeremyje/coretemp-exporter/drivers/common"
 func New() common.Driver {
 	return newDriver()
 }
+
+// Probe returns an error that explains why Core Temp cannot be read on this machine, or nil if it can.
+func Probe() error {
+	return probe()
+}
@@ -20,14 +20,12 @@ package coretempsdk
 import (
 	"fmt"
 	"os"
-	"strings"
 	"sync"
 	"unsafe"
 
 	"github.com/jeremyje/coretemp-exporter/drivers/common"
 	pb "github.com/jeremyje/coretemp-exporter/proto"
 	"golang.org/x/sys/windows"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var (
@@ -45,99 +43,14 @@ const (
 	enableAutoDownload = false
 )
 
-// https://www.alcpu.com/CoreTemp/developers.html
-
-type coreTempSharedDataEx struct {
-	// Original structure (CoreTempSharedData)
-	uiLoad      [256]uint32
-	uiTjMax     [128]uint32
-	uiCoreCnt   uint32
-	uiCPUCnt    uint32
-	fTemp       [256]float32
-	fVID        float32
-	fCPUSpeed   float32
-	fFSBSpeed   float32
-	fMultiplier float32
-	sCPUName    [100]byte
-	// If ucFahrenheit is true, the temperature is reported in Fahrenheit.
-	ucFahrenheit byte
-	// If ucDeltaToTjMax is true, the temperature reported represents the distance from TjMax.
-	ucDeltaToTjMax byte
-
-	// uiStructVersion = 2
-
-	// If ucTdpSupported is true, processor TDP information in the uiTdp array is valid.
-	ucTdpSupported byte
-	// If ucPowerSupported is true, processor power consumption information in the fPower array is valid.
-	ucPowerSupported byte
-	uiStructVersion  uint32
-	uiTdp            [128]uint32
-	fPower           [128]float32
-	fMultipliers     [256]float32
-}
-
-func getCoreTempInfo() (*pb.MachineMetrics, error) {
-	rawInfo, err := getCoreTempInfoAlt()
+func getCoreTempInfo(energy *EnergyMeter) (*pb.MachineMetrics, error) {
+	data, err := getCoreTempInfoAlt()
 	if err != nil {
 		return nil, err
 	}
-
-	coreCount := int(rawInfo.uiCoreCnt)
-
-	temps := float64List(rawInfo.fTemp[:], coreCount)
-
-	if byteToBool(rawInfo.ucFahrenheit) {
-		for i := 0; i < len(temps); i++ {
-			temps[i] = fToC(temps[i])
-		}
-	}
-
-	return &pb.MachineMetrics{
-		Name:      common.Hostname(),
-		Timestamp: timestamppb.Now(),
-		Device: []*pb.DeviceMetrics{
-			{
-				Name:        cleanString(string(rawInfo.sCPUName[:])),
-				Kind:        "cpu",
-				Temperature: common.Average(temps),
-				Cpu: &pb.CpuDeviceMetrics{
-					Load:            int32List(rawInfo.uiLoad[:], coreCount),
-					Temperature:     temps,
-					NumCores:        int32(coreCount),
-					FrequencyMhz:    float64(rawInfo.fCPUSpeed),
-					FsbFrequencyMhz: float64(rawInfo.fFSBSpeed),
-				},
-			},
-		},
-	}, nil
-}
-
-func fToC(f float64) float64 {
-	return (f - 32) * 5 / 9
-}
-
-func byteToBool(b byte) bool {
-	return b != 0
-}
-
-func int32List[T uint32 | int32](input []T, size int) []int32 {
-	result := make([]int32, size)
-	for i := 0; i < int(size); i++ {
-		result[i] = int32(input[i])
-	}
-	return result
-}
-
-func float64List[T float32 | float64 | int | uint32](input []T, size int) []float64 {
-	result := make([]float64, size)
-	for i := 0; i < int(size); i++ {
-		result[i] = float64(input[i])
-	}
-	return result
-}
-
-func cleanString(input string) string {
-	return strings.TrimSpace(strings.Trim(input, string("\x00")))
+	mm := ToMachineMetrics(data)
+	energy.Update(mm)
+	return mm, nil
 }
 
 type coreTempSDKError struct {
@@ -191,19 +104,19 @@ func getFnGetCoreTempInfo() (*windows.LazyProc, error) {
 	return globalFnGetCoreTempInfo, nil
 }
 
-func getCoreTempInfoAlt() (*coreTempSharedDataEx, error) {
+func getCoreTempInfoAlt() (*SharedData, error) {
 	fnGetCoreTempInfo, err := getFnGetCoreTempInfo()
 	if err != nil {
 		return nil, wrapCallError(err)
 	}
 
-	rawInfo := &coreTempSharedDataEx{}
-	r1, _, err := fnGetCoreTempInfo.Call(uintptr(unsafe.Pointer(rawInfo)))
+	data := &SharedData{}
+	r1, _, err := fnGetCoreTempInfo.Call(uintptr(unsafe.Pointer(data)))
 
 	if r1 != 1 {
 		return nil, err
 	}
-	return rawInfo, nil
+	return data, nil
 }
 
 func ensureCoreTempDLL() error {
@@ -229,12 +142,20 @@ func ensureCoreTempDLL() error {
 }
 
 type coreTempSDKDriver struct {
+	energy *EnergyMeter
 }
 
 func (d *coreTempSDKDriver) Get() (*pb.MachineMetrics, error) {
-	return getCoreTempInfo()
+	return getCoreTempInfo(d.energy)
 }
 
 func newDriver() common.Driver {
-	return &coreTempSDKDriver{}
+	return &coreTempSDKDriver{
+		energy: NewEnergyMeter(),
+	}
+}
+
+func probe() error {
+	_, err := getFnGetCoreTempInfo()
+	return err
 }
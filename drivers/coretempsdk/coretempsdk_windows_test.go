@@ -20,7 +20,7 @@ package coretempsdk
 import "testing"
 
 func TestGetCoreTempInfo(t *testing.T) {
-	info, err := getCoreTempInfo()
+	info, err := getCoreTempInfo(NewEnergyMeter())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,68 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coretempsdk
+
+import (
+	"sync"
+	"time"
+
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+// EnergyMeter turns the package power of Core Temp into a cumulative energy counter, like the RAPL counters on Linux.
+// Core Temp only reports the power so the energy is integrated between the timestamps of two readings, with the average of their power.
+type EnergyMeter struct {
+	mu     sync.Mutex
+	last   time.Time
+	watts  map[int32]float64
+	joules map[int32]float64
+}
+
+// NewEnergyMeter creates an EnergyMeter that starts counting from 0 J.
+func NewEnergyMeter() *EnergyMeter {
+	return &EnergyMeter{
+		watts:  map[int32]float64{},
+		joules: map[int32]float64{},
+	}
+}
+
+// Update sets the package energy of every CPU in mm that reports its power. The first reading only starts the count.
+// A reading that is not newer than the previous one, like the same message of a Remote Server read twice, does not add anything.
+func (e *EnergyMeter) Update(mm *pb.MachineMetrics) {
+	now := mm.GetTimestamp().AsTime()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	elapsed := now.Sub(e.last).Seconds()
+	first := e.last.IsZero()
+	if first || elapsed > 0 {
+		e.last = now
+	}
+	for _, device := range mm.GetDevice() {
+		cpu := device.GetCpu()
+		if cpu == nil || cpu.GetPackagePowerWatts() <= 0 {
+			continue
+		}
+		socket := cpu.GetSocket()
+		watts := cpu.GetPackagePowerWatts()
+		if prev, ok := e.watts[socket]; ok && !first && elapsed > 0 {
+			e.joules[socket] += (prev + watts) / 2 * elapsed
+		}
+		if first || elapsed > 0 {
+			e.watts[socket] = watts
+		}
+		cpu.PackageEnergyJoules = e.joules[socket]
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coretempsdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestEnergyMeter(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	reading := func(seconds int, watts ...float64) *pb.MachineMetrics {
+		mm := &pb.MachineMetrics{Timestamp: timestamppb.New(start.Add(time.Duration(seconds) * time.Second))}
+		for socket, w := range watts {
+			mm.Device = append(mm.Device, &pb.DeviceMetrics{Kind: "cpu", Cpu: &pb.CpuDeviceMetrics{Socket: int32(socket), PackagePowerWatts: w}})
+		}
+		// A voltage device does not have a CPU.
+		mm.Device = append(mm.Device, &pb.DeviceMetrics{Kind: "voltage"})
+		return mm
+	}
+
+	e := NewEnergyMeter()
+	got := [][]float64{}
+	for _, mm := range []*pb.MachineMetrics{
+		reading(0, 10, 20),
+		// 2s at an average of 15 W and 20 W.
+		reading(2, 20, 20),
+		// The same reading again does not count twice.
+		reading(2, 20, 20),
+		// CPU 1 does not report its power.
+		reading(3, 40, 0),
+	} {
+		e.Update(mm)
+		joules := []float64{}
+		for _, device := range mm.GetDevice() {
+			if device.GetCpu() != nil {
+				joules = append(joules, device.GetCpu().GetPackageEnergyJoules())
+			}
+		}
+		got = append(got, joules)
+	}
+
+	want := [][]float64{{0, 0}, {30, 40}, {30, 40}, {60, 0}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("PackageEnergyJoules mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coretempsdk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SharedData is CORE_TEMP_SHARED_DATA_EX from the Core Temp SDK, https://www.alcpu.com/CoreTemp/developers.html
+// The fields are in the same order and have the same sizes as the C struct so a pointer to SharedData can be passed to GetCoreTempInfo.dll.
+type SharedData struct {
+	// Original structure (CoreTempSharedData)
+
+	// Load of each core in percent.
+	Load [256]uint32
+	// TjMax of each CPU in Celsius.
+	TjMax [128]uint32
+	// CoreCnt is the number of cores of each CPU.
+	CoreCnt uint32
+	// CPUCnt is the number of CPUs.
+	CPUCnt uint32
+	// Temp of each core, in Fahrenheit if Fahrenheit is set and as the distance to TjMax if DeltaToTjMax is set.
+	Temp [256]float32
+	// VID is the voltage requested by the first CPU.
+	VID        float32
+	CPUSpeed   float32
+	FSBSpeed   float32
+	Multiplier float32
+	CPUName    [100]byte
+	// If Fahrenheit is true, the temperature is reported in Fahrenheit.
+	Fahrenheit byte
+	// If DeltaToTjMax is true, the temperature reported represents the distance from TjMax.
+	DeltaToTjMax byte
+
+	// StructVersion = 2
+
+	// If TdpSupported is true, processor TDP information in the Tdp array is valid.
+	TdpSupported byte
+	// If PowerSupported is true, processor power consumption information in the Power array is valid.
+	PowerSupported byte
+	StructVersion  uint32
+	// Tdp of each CPU in watts.
+	Tdp [128]uint32
+	// Power drawn by each CPU in watts.
+	Power [128]float32
+	// Multipliers of each core.
+	Multipliers [256]float32
+}
+
+const (
+	// sharedDataV1Size is the size of CoreTempSharedData, version 1 of the struct ends after DeltaToTjMax.
+	sharedDataV1Size = 2686
+)
+
+var (
+	sharedDataSize = binary.Size(&SharedData{})
+)
+
+// DecodeSharedData reads the little-endian bytes of the struct filled in by GetCoreTempInfo.dll.
+// A buffer of version 1 of the struct, which is shorter, is accepted and the fields of version 2 are left empty.
+func DecodeSharedData(buf []byte) (*SharedData, error) {
+	if len(buf) < sharedDataV1Size {
+		return nil, fmt.Errorf("cannot decode Core Temp shared data, got %d bytes but it has at least %d bytes", len(buf), sharedDataV1Size)
+	}
+	full := make([]byte, sharedDataSize)
+	copy(full, buf)
+
+	data := &SharedData{}
+	if err := binary.Read(bytes.NewReader(full), binary.LittleEndian, data); err != nil {
+		return nil, fmt.Errorf("cannot decode Core Temp shared data, err= %w", err)
+	}
+	if data.StructVersion < 2 {
+		// Whatever follows a version 1 struct is not part of it.
+		data.TdpSupported = 0
+		data.PowerSupported = 0
+		data.Tdp = [128]uint32{}
+		data.Power = [128]float32{}
+		data.Multipliers = [256]float32{}
+	}
+	return data, nil
+}
+
+// ToMachineMetrics converts the shared data into one device for each CPU, and a voltage device for the VID.
+// The temperatures are always reported in Celsius, as absolute temperatures.
+func ToMachineMetrics(data *SharedData) *pb.MachineMetrics {
+	coreCount := int(data.CoreCnt)
+	cpuCount := int(data.CPUCnt)
+	if cpuCount < 1 {
+		cpuCount = 1
+	}
+	cpuName := cleanString(string(data.CPUName[:]))
+	fahrenheit := byteToBool(data.Fahrenheit)
+	deltaToTjMax := byteToBool(data.DeltaToTjMax)
+	version2 := data.StructVersion >= 2
+
+	// The per-core arrays hold CoreCnt entries for the first CPU followed by the entries of the next CPU.
+	devices := []*pb.DeviceMetrics{}
+	for cpu := 0; cpu < cpuCount && cpu < len(data.TjMax) && (cpu+1)*coreCount <= len(data.Temp); cpu++ {
+		offset := cpu * coreCount
+		// Core Temp only reports TjMax, it is both the throttle point and the critical temperature of every core.
+		tjMax := float64(data.TjMax[cpu])
+
+		temps := float64List(data.Temp[offset:], coreCount)
+		tempMax := make([]float64, coreCount)
+		tempCrit := make([]float64, coreCount)
+		tempAlarm := make([]bool, coreCount)
+		for i := range temps {
+			switch {
+			case deltaToTjMax && fahrenheit:
+				// TjMax is in Celsius, only the distance to it is in Fahrenheit degrees.
+				temps[i] = tjMax - temps[i]*5/9
+			case deltaToTjMax:
+				temps[i] = tjMax - temps[i]
+			case fahrenheit:
+				temps[i] = fToC(temps[i])
+			}
+			tempMax[i] = tjMax
+			tempCrit[i] = tjMax
+			tempAlarm[i] = tjMax > 0 && temps[i] >= tjMax
+		}
+
+		cpuMetrics := &pb.CpuDeviceMetrics{
+			Load:            int32List(data.Load[offset:], coreCount),
+			Temperature:     temps,
+			NumCores:        int32(coreCount),
+			FrequencyMhz:    float64(data.CPUSpeed),
+			FsbFrequencyMhz: float64(data.FSBSpeed),
+			Socket:          int32(cpu),
+			// Core Temp does not report the package temperature, the hottest core is the closest to what the CPU throttles on.
+			PackageTemperature: maxFloat64(temps),
+			TemperatureMax:     tempMax,
+			TemperatureCrit:    tempCrit,
+			TemperatureAlarm:   tempAlarm,
+			Multiplier:         float64(data.Multiplier),
+		}
+		// The power, TDP and per-core multipliers were added in version 2 of the shared data.
+		if version2 && byteToBool(data.PowerSupported) {
+			cpuMetrics.PackagePowerWatts = float64(data.Power[cpu])
+		}
+		if version2 && byteToBool(data.TdpSupported) {
+			cpuMetrics.TdpWatts = float64(data.Tdp[cpu])
+		}
+		if version2 {
+			cpuMetrics.CoreMultiplier = float64List(data.Multipliers[offset:], coreCount)
+			if data.FSBSpeed > 0 {
+				cpuMetrics.CoreFrequencyHz = make([]float64, coreCount)
+				for i, multiplier := range cpuMetrics.CoreMultiplier {
+					cpuMetrics.CoreFrequencyHz[i] = multiplier * float64(data.FSBSpeed) * 1000 * 1000
+				}
+			}
+		}
+
+		devices = append(devices, &pb.DeviceMetrics{
+			Name:        cpuName,
+			Kind:        "cpu",
+			Temperature: common.Average(temps),
+			Cpu:         cpuMetrics,
+		})
+	}
+
+	// Core Temp reports a single VID (requested core voltage) for the first CPU.
+	if data.VID > 0 {
+		devices = append(devices, &pb.DeviceMetrics{
+			Name: cpuName,
+			Kind: "voltage",
+			Voltage: &pb.VoltageDeviceMetrics{
+				Label: "VID",
+				Volts: float64(data.VID),
+			},
+		})
+	}
+
+	return &pb.MachineMetrics{
+		Name:      common.Hostname(),
+		Timestamp: timestamppb.Now(),
+		Device:    devices,
+	}
+}
+
+func maxFloat64(val []float64) float64 {
+	result := float64(0.0)
+	for i, v := range val {
+		if i == 0 || v > result {
+			result = v
+		}
+	}
+	return result
+}
+
+func fToC(f float64) float64 {
+	return (f - 32) * 5 / 9
+}
+
+func byteToBool(b byte) bool {
+	return b != 0
+}
+
+func int32List[T uint32 | int32](input []T, size int) []int32 {
+	result := make([]int32, size)
+	for i := 0; i < int(size); i++ {
+		result[i] = int32(input[i])
+	}
+	return result
+}
+
+func float64List[T float32 | float64 | int | uint32](input []T, size int) []float64 {
+	result := make([]float64, size)
+	for i := 0; i < int(size); i++ {
+		result[i] = float64(input[i])
+	}
+	return result
+}
+
+func cleanString(input string) string {
+	return strings.TrimSpace(strings.Trim(input, string("\x00")))
+}
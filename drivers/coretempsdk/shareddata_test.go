@@ -0,0 +1,256 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coretempsdk
+
+import (
+	_ "embed"
+	"testing"
+	"unsafe"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+var (
+	//go:embed testdata/shareddata_v1.bin
+	sharedDataV1Bin []byte
+	//go:embed testdata/shareddata_v2.bin
+	sharedDataV2Bin []byte
+)
+
+func TestSharedDataLayout(t *testing.T) {
+	// GetCoreTempInfo.dll writes straight into SharedData so it must match the C struct.
+	data := SharedData{}
+	for _, tc := range []struct {
+		name string
+		got  uintptr
+		want uintptr
+	}{
+		{name: "size", got: unsafe.Sizeof(data), want: 4740},
+		{name: "binary size", got: uintptr(sharedDataSize), want: 4740},
+		{name: "CoreCnt", got: unsafe.Offsetof(data.CoreCnt), want: 1536},
+		{name: "Temp", got: unsafe.Offsetof(data.Temp), want: 1544},
+		{name: "CPUName", got: unsafe.Offsetof(data.CPUName), want: 2584},
+		{name: "Fahrenheit", got: unsafe.Offsetof(data.Fahrenheit), want: 2684},
+		{name: "DeltaToTjMax", got: unsafe.Offsetof(data.DeltaToTjMax), want: 2685},
+		{name: "StructVersion", got: unsafe.Offsetof(data.StructVersion), want: 2688},
+		{name: "Tdp", got: unsafe.Offsetof(data.Tdp), want: 2692},
+		{name: "Power", got: unsafe.Offsetof(data.Power), want: 3204},
+		{name: "Multipliers", got: unsafe.Offsetof(data.Multipliers), want: 3716},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %d, want %d", tc.name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestDecodeSharedData(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  *pb.MachineMetrics
+	}{
+		{
+			name:  "shareddata_v1.bin",
+			input: sharedDataV1Bin,
+			want: &pb.MachineMetrics{
+				Device: []*pb.DeviceMetrics{
+					{
+						Name:        "Intel(R) Core(TM) i5-2500K CPU @ 3.30GHz",
+						Kind:        "cpu",
+						Temperature: 42.5,
+						Cpu: &pb.CpuDeviceMetrics{
+							Load:               []int32{5, 10, 0, 100},
+							Temperature:        []float64{40, 45, 50, 35},
+							NumCores:           4,
+							FrequencyMhz:       3300,
+							FsbFrequencyMhz:    100,
+							PackageTemperature: 50,
+							TemperatureMax:     []float64{98, 98, 98, 98},
+							TemperatureCrit:    []float64{98, 98, 98, 98},
+							TemperatureAlarm:   []bool{false, false, false, false},
+							Multiplier:         33,
+						},
+					},
+					{
+						Name: "Intel(R) Core(TM) i5-2500K CPU @ 3.30GHz",
+						Kind: "voltage",
+						Voltage: &pb.VoltageDeviceMetrics{
+							Label: "VID",
+							Volts: 1.25,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "shareddata_v2.bin",
+			input: sharedDataV2Bin,
+			want: &pb.MachineMetrics{
+				Device: []*pb.DeviceMetrics{
+					{
+						Name:        "Intel(R) Xeon(R) CPU E5-2670 0 @ 2.60GHz",
+						Kind:        "cpu",
+						Temperature: 51,
+						Cpu: &pb.CpuDeviceMetrics{
+							Load:               []int32{20, 40},
+							Temperature:        []float64{50, 52},
+							NumCores:           2,
+							FrequencyMhz:       2600,
+							FsbFrequencyMhz:    100,
+							PackageTemperature: 52,
+							TemperatureMax:     []float64{100, 100},
+							TemperatureCrit:    []float64{100, 100},
+							TemperatureAlarm:   []bool{false, false},
+							PackagePowerWatts:  45.5,
+							TdpWatts:           115,
+							Multiplier:         26,
+							CoreMultiplier:     []float64{26, 33},
+							CoreFrequencyHz:    []float64{2600000000, 3300000000},
+						},
+					},
+					{
+						Name:        "Intel(R) Xeon(R) CPU E5-2670 0 @ 2.60GHz",
+						Kind:        "cpu",
+						Temperature: 55.5,
+						Cpu: &pb.CpuDeviceMetrics{
+							Load:               []int32{60, 80},
+							Temperature:        []float64{55, 56},
+							NumCores:           2,
+							FrequencyMhz:       2600,
+							FsbFrequencyMhz:    100,
+							Socket:             1,
+							PackageTemperature: 56,
+							TemperatureMax:     []float64{95, 95},
+							TemperatureCrit:    []float64{95, 95},
+							TemperatureAlarm:   []bool{false, false},
+							PackagePowerWatts:  60.25,
+							TdpWatts:           115,
+							Multiplier:         26,
+							CoreMultiplier:     []float64{12, 26},
+							CoreFrequencyHz:    []float64{1200000000, 2600000000},
+						},
+					},
+				},
+			},
+		},
+		{
+			// Only the fields of version 1 are in the buffer.
+			name:  "shareddata_v1.bin without padding",
+			input: sharedDataV1Bin[:sharedDataV1Size],
+			want: &pb.MachineMetrics{
+				Device: []*pb.DeviceMetrics{
+					{
+						Name:        "Intel(R) Core(TM) i5-2500K CPU @ 3.30GHz",
+						Kind:        "cpu",
+						Temperature: 42.5,
+						Cpu: &pb.CpuDeviceMetrics{
+							Load:               []int32{5, 10, 0, 100},
+							Temperature:        []float64{40, 45, 50, 35},
+							NumCores:           4,
+							FrequencyMhz:       3300,
+							FsbFrequencyMhz:    100,
+							PackageTemperature: 50,
+							TemperatureMax:     []float64{98, 98, 98, 98},
+							TemperatureCrit:    []float64{98, 98, 98, 98},
+							TemperatureAlarm:   []bool{false, false, false, false},
+							Multiplier:         33,
+						},
+					},
+					{
+						Name: "Intel(R) Core(TM) i5-2500K CPU @ 3.30GHz",
+						Kind: "voltage",
+						Voltage: &pb.VoltageDeviceMetrics{
+							Label: "VID",
+							Volts: 1.25,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := DecodeSharedData(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ToMachineMetrics(data)
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform(), protocmp.IgnoreFields(&pb.MachineMetrics{}, "name", "timestamp")); diff != "" {
+				t.Errorf("ToMachineMetrics() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeSharedDataVersion1IgnoresTrailingData(t *testing.T) {
+	buf := append([]byte{}, sharedDataV2Bin...)
+	// Clear StructVersion, the version 2 fields after it are left over memory.
+	copy(buf[2688:2692], []byte{1, 0, 0, 0})
+	data, err := DecodeSharedData(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpu := ToMachineMetrics(data).GetDevice()[0].GetCpu()
+	if cpu.GetPackagePowerWatts() != 0 || cpu.GetTdpWatts() != 0 || len(cpu.GetCoreMultiplier()) != 0 || len(cpu.GetCoreFrequencyHz()) != 0 {
+		t.Errorf("version 1 should not report the fields of version 2, got %v", cpu)
+	}
+}
+
+func TestDecodeSharedDataTooShort(t *testing.T) {
+	if _, err := DecodeSharedData(sharedDataV1Bin[:100]); err == nil {
+		t.Error("DecodeSharedData() should fail")
+	}
+}
+
+func TestToMachineMetricsTemperature(t *testing.T) {
+	tests := []struct {
+		name         string
+		fahrenheit   byte
+		deltaToTjMax byte
+		temp         float32
+		want         float64
+		wantAlarm    bool
+	}{
+		{name: "celsius", temp: 60, want: 60},
+		{name: "fahrenheit", fahrenheit: 1, temp: 140, want: 60},
+		{name: "delta to TjMax", deltaToTjMax: 1, temp: 40, want: 60},
+		{name: "delta to TjMax in fahrenheit", fahrenheit: 1, deltaToTjMax: 1, temp: 72, want: 60},
+		{name: "at TjMax", deltaToTjMax: 1, temp: 0, want: 100, wantAlarm: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := &SharedData{CoreCnt: 1, CPUCnt: 1, Fahrenheit: tc.fahrenheit, DeltaToTjMax: tc.deltaToTjMax}
+			data.TjMax[0] = 100
+			data.Temp[0] = tc.temp
+			cpu := ToMachineMetrics(data).GetDevice()[0].GetCpu()
+			if diff := cmp.Diff([]float64{tc.want}, cpu.GetTemperature()); diff != "" {
+				t.Errorf("temperature mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff([]bool{tc.wantAlarm}, cpu.GetTemperatureAlarm()); diff != "" {
+				t.Errorf("alarm mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -19,9 +19,49 @@ package drivers
 
 import (
 	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	"github.com/jeremyje/coretemp-exporter/drivers/composite"
+	"github.com/jeremyje/coretemp-exporter/drivers/hwmon"
 	"github.com/jeremyje/coretemp-exporter/drivers/lmsensors"
+	"github.com/jeremyje/coretemp-exporter/drivers/raspberrypi"
+	"github.com/jeremyje/coretemp-exporter/drivers/thermal"
 )
 
+// New returns the raspberrypi driver, which reads the other hwmon sensors of the board as well, on a Raspberry Pi, otherwise the hwmon driver if the kernel exposes sensors, otherwise it falls back to lm-sensors.
+// The thermal zones are read as well when there is no hwmon chip for the CPU, many ARM boards only report their temperatures there.
 func New() common.Driver {
-	return lmsensors.New()
+	var primary common.Driver
+	// The SoC temperature of a Raspberry Pi is the temperature of its CPU.
+	cpuTemperatures := false
+	switch {
+	case raspberrypi.Available():
+		primary = raspberrypi.New()
+		cpuTemperatures = true
+	case hwmon.Available():
+		primary = hwmon.New()
+	case lmsensors.Probe() == nil:
+		primary = lmsensors.New()
+	}
+	if !cpuTemperatures {
+		cpuTemperatures = hasCPUTemperatures(lmsensors.DefaultSysfsRoot)
+	}
+	if !thermal.Available() || cpuTemperatures {
+		if primary == nil {
+			return lmsensors.New()
+		}
+		return primary
+	}
+	if primary == nil {
+		return thermal.New()
+	}
+	return composite.New(primary, thermal.New())
+}
+
+// hasCPUTemperatures returns true if the sysfs tree mounted at root has a hwmon chip for the CPU, in which case the thermal zones would only report it again.
+// lm-sensors reads the same chips. The chips are listed without reading the sensors so a transient failure cannot change which drivers are used.
+func hasCPUTemperatures(root string) bool {
+	chips, err := hwmon.ReadChips(root)
+	if err != nil {
+		return false
+	}
+	return lmsensors.HasCPUTemperatures(chips)
 }
@@ -0,0 +1,59 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package drivers
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestHasCPUTemperatures(t *testing.T) {
+	tests := []struct {
+		name string
+		root string
+		want bool
+	}{
+		{
+			name: "coretemp",
+			root: filepath.Join("hwmon", "testdata", "sys"),
+			want: true,
+		},
+		{
+			// A board without a hwmon adapter for its CPU, its temperature is only in the thermal zones.
+			name: "no CPU chip",
+			root: filepath.Join("raspberrypi", "testdata", "pi4", "sys"),
+			want: false,
+		},
+		{
+			name: "no hwmon",
+			root: filepath.Join("testdata", "does-not-exist"),
+			want: false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := hasCPUTemperatures(tc.root); got != tc.want {
+				t.Errorf("hasCPUTemperatures() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
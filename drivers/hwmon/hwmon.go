@@ -0,0 +1,240 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hwmon reads sensor data directly from the Linux hwmon sysfs interface.
+// See https://www.kernel.org/doc/html/latest/hwmon/sysfs-interface.html
+package hwmon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	"github.com/jeremyje/coretemp-exporter/drivers/lmsensors"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+const (
+	hwmonClassDir = "class/hwmon"
+)
+
+var (
+	subfeatureRegexp = regexp.MustCompile(`^([a-z]+)(\d+)_([a-z_]+)$`)
+	// pwmRegexp matches the duty cycle (0-255) of the fan with the same number.
+	pwmRegexp = regexp.MustCompile(`^pwm(\d+)$`)
+	// subfeatureDivisor converts the raw sysfs values into the units used by lm-sensors.
+	subfeatureDivisor = map[string]float64{
+		"temp":     1000,
+		"fan":      1,
+		"in":       1000,
+		"power":    1000 * 1000,
+		"energy":   1000 * 1000,
+		"curr":     1000,
+		"humidity": 1000,
+		"freq":     1,
+	}
+)
+
+// New creates a driver that reads hwmon sensors from /sys.
+func New() common.Driver {
+	return NewWithRoot(lmsensors.DefaultSysfsRoot)
+}
+
+// NewWithRoot creates a driver that reads hwmon sensors from a sysfs tree mounted at root.
+func NewWithRoot(root string) common.Driver {
+	return &hwmonDriver{
+		root: root,
+		host: lmsensors.NewHostWithRoot(lmsensors.DefaultProcRoot, root),
+	}
+}
+
+// Available returns true if there are hwmon temperature sensors under /sys.
+func Available() bool {
+	return Probe() == nil
+}
+
+// Probe returns an error that explains why there are no hwmon sensors under /sys, or nil if there are.
+func Probe() error {
+	chips, err := ReadChips(lmsensors.DefaultSysfsRoot)
+	if err != nil {
+		return err
+	}
+	if len(chips) == 0 {
+		return fmt.Errorf("cannot find any hwmon sensors in '%s'", filepath.Join(lmsensors.DefaultSysfsRoot, hwmonClassDir))
+	}
+	return nil
+}
+
+type hwmonDriver struct {
+	root string
+	host *lmsensors.Host
+}
+
+func (d *hwmonDriver) Get() (*pb.MachineMetrics, error) {
+	chips, err := ReadChips(d.root)
+	if err != nil {
+		return nil, err
+	}
+	if len(chips) == 0 {
+		return nil, fmt.Errorf("cannot find any hwmon sensors in '%s'", filepath.Join(d.root, hwmonClassDir))
+	}
+	return d.host.Metrics(chips), nil
+}
+
+// ReadChips reads every hwmon chip with at least one sensor from a sysfs tree mounted at root.
+func ReadChips(root string) ([]*lmsensors.Chip, error) {
+	classDir := filepath.Join(root, hwmonClassDir)
+	entries, err := os.ReadDir(classDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read '%s', err= %w", classDir, err)
+	}
+
+	chips := []*lmsensors.Chip{}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "hwmon") {
+			continue
+		}
+		chip, err := readChip(filepath.Join(classDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if len(chip.Features) > 0 {
+			chips = append(chips, chip)
+		}
+	}
+	sort.Slice(chips, func(i, j int) bool {
+		return chips[i].ID < chips[j].ID
+	})
+	return chips, nil
+}
+
+func readChip(dir string) (*lmsensors.Chip, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read '%s', err= %w", dir, err)
+	}
+
+	name := readString(filepath.Join(dir, "name"))
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+	id, adapter := chipID(dir, name)
+
+	chip := &lmsensors.Chip{
+		ID:       id,
+		Adapter:  adapter,
+		Features: map[string]lmsensors.Feature{},
+	}
+	feature := func(kind string, index string) lmsensors.Feature {
+		label := readString(filepath.Join(dir, kind+index+"_label"))
+		if label == "" {
+			label = kind + index
+		}
+		f, ok := chip.Features[label]
+		if !ok {
+			f = lmsensors.Feature{}
+			chip.Features[label] = f
+		}
+		return f
+	}
+	for _, entry := range entries {
+		if m := pwmRegexp.FindStringSubmatch(entry.Name()); m != nil {
+			if value, ok := readFloat(filepath.Join(dir, entry.Name())); ok {
+				// lm-sensors does not report the PWM so it is stored with the fan as a percentage.
+				feature("fan", m[1])["fan"+m[1]+"_pwm"] = value * 100 / 255
+			}
+			continue
+		}
+		m := subfeatureRegexp.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		kind, index, subfeature := m[1], m[2], m[3]
+		divisor, ok := subfeatureDivisor[kind]
+		if !ok || subfeature == "label" || subfeature == "type" {
+			continue
+		}
+		value, ok := readFloat(filepath.Join(dir, entry.Name()))
+		if !ok {
+			// Sensors that are not available return errors like ENODATA when read.
+			continue
+		}
+		feature(kind, index)[entry.Name()] = value / divisor
+	}
+	return chip, nil
+}
+
+// chipID builds the same chip name that libsensors would use, for example "coretemp-isa-0000".
+func chipID(dir string, name string) (string, string) {
+	device, err := filepath.EvalSymlinks(filepath.Join(dir, "device"))
+	if err != nil {
+		return name + "-virtual-0", "Virtual device"
+	}
+	deviceName := filepath.Base(device)
+	subsystem := ""
+	if s, err := filepath.EvalSymlinks(filepath.Join(device, "subsystem")); err == nil {
+		subsystem = filepath.Base(s)
+	}
+
+	switch subsystem {
+	case "platform", "isa":
+		addr := 0
+		if i := strings.LastIndex(deviceName, "."); i >= 0 {
+			addr, _ = strconv.Atoi(deviceName[i+1:])
+		}
+		return fmt.Sprintf("%s-isa-%04x", name, addr), "ISA adapter"
+	case "pci":
+		if id, ok := lmsensors.PCIChipID(name, deviceName); ok {
+			return id, "PCI adapter"
+		}
+	case "nvme":
+		// The hwmon device of a NVMe drive is the controller, like ".../0000:01:00.0/nvme/nvme0", which is named after its PCI device.
+		if id, ok := lmsensors.PCIChipID(name, filepath.Base(filepath.Dir(filepath.Dir(device)))); ok {
+			return id, "PCI adapter"
+		}
+	case "scsi":
+		if id, ok := lmsensors.SCSIChipID(name, deviceName); ok {
+			return id, "SCSI adapter"
+		}
+	case "acpi":
+		return name + "-acpi-0", "ACPI interface"
+	case "i2c":
+		var bus, addr int
+		if _, err := fmt.Sscanf(deviceName, "%d-%x", &bus, &addr); err == nil {
+			return fmt.Sprintf("%s-i2c-%d-%x", name, bus, addr), fmt.Sprintf("SMBus adapter %d", bus)
+		}
+	}
+	return name + "-" + filepath.Base(dir), subsystem
+}
+
+func readFloat(name string) (float64, bool) {
+	value, err := strconv.ParseFloat(readString(name), 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+func readString(name string) string {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,197 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hwmon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jeremyje/coretemp-exporter/drivers/lmsensors"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const (
+	testSysfsRoot = "testdata/sys"
+)
+
+func ExampleNew() {
+	info, err := New().Get()
+	if err != nil {
+		fmt.Printf("ERROR: %s", err)
+	}
+	fmt.Printf("hwmon: %+v", info)
+}
+
+func TestReadChips(t *testing.T) {
+	got, err := ReadChips(testSysfsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*lmsensors.Chip{
+		{
+			ID:      "acpitz-acpi-0",
+			Adapter: "ACPI interface",
+			Features: map[string]lmsensors.Feature{
+				"temp1": {"temp1_input": 44, "temp1_crit": 95},
+			},
+		},
+		{
+			ID:      "coretemp-isa-0000",
+			Adapter: "ISA adapter",
+			Features: map[string]lmsensors.Feature{
+				"Package id 0": {"temp1_input": 45, "temp1_max": 105, "temp1_crit": 105, "temp1_crit_alarm": 0},
+				"Core 0":       {"temp2_input": 44, "temp2_max": 105, "temp2_crit": 105, "temp2_crit_alarm": 0},
+				"Core 1":       {"temp3_input": 47, "temp3_max": 105, "temp3_crit": 105, "temp3_crit_alarm": 0},
+			},
+		},
+		{
+			ID:      "nct6775-isa-0290",
+			Adapter: "ISA adapter",
+			Features: map[string]lmsensors.Feature{
+				"CPU Fan": {"fan1_input": 1200, "fan1_min": 300, "fan1_alarm": 0, "fan1_pwm": 100},
+				"fan2":    {"fan2_input": 0, "fan2_min": 300, "fan2_alarm": 1, "fan2_pwm": 0},
+				"Vcore":   {"in0_input": 1.056, "in0_min": 0.9, "in0_max": 1.5, "in0_alarm": 0},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ReadChips() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGet(t *testing.T) {
+	got, err := NewWithRoot(testSysfsRoot).Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pb.MachineMetrics{
+		Device: []*pb.DeviceMetrics{
+			{
+				Kind:        "cpu",
+				Temperature: 45.5,
+				Cpu: &pb.CpuDeviceMetrics{
+					Load:               []int32{},
+					NumCores:           2,
+					Temperature:        []float64{44, 47},
+					PackageTemperature: 45,
+					TemperatureMax:     []float64{105, 105},
+					TemperatureCrit:    []float64{105, 105},
+					TemperatureAlarm:   []bool{false, false},
+				},
+			},
+			{
+				Kind: "fan",
+				Chip: "nct6775-isa-0290",
+				Fan: &pb.FanDeviceMetrics{
+					Label:      "CPU Fan",
+					Rpm:        1200,
+					MinRpm:     300,
+					PwmPercent: 100,
+				},
+			},
+			{
+				Kind: "fan",
+				Chip: "nct6775-isa-0290",
+				Fan: &pb.FanDeviceMetrics{
+					Label:  "fan2",
+					MinRpm: 300,
+					Alarm:  true,
+				},
+			},
+			{
+				Kind: "voltage",
+				Chip: "nct6775-isa-0290",
+				Voltage: &pb.VoltageDeviceMetrics{
+					Label:    "Vcore",
+					Volts:    1.056,
+					MinVolts: 0.9,
+					MaxVolts: 1.5,
+				},
+			},
+			{
+				Name: "acpitz-acpi-0",
+				Kind: "sensor",
+				Chip: "acpitz-acpi-0",
+				Sensor: []*pb.SensorReading{
+					{Label: "temp1", Type: "temp", Unit: "C", Value: 44, Crit: 95},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform(), protocmp.IgnoreFields(&pb.MachineMetrics{}, "name", "timestamp"), protocmp.IgnoreFields(&pb.DeviceMetrics{}, "name"), protocmp.IgnoreFields(&pb.CpuDeviceMetrics{}, "frequency_mhz", "core_frequency_hz", "core_frequency_min_hz", "core_frequency_max_hz")); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetMissingRoot(t *testing.T) {
+	mm, err := NewWithRoot(t.TempDir()).Get()
+	if err == nil {
+		t.Error("expected an error when there is no hwmon directory")
+	}
+	if mm != nil {
+		t.Errorf("MachineMetrics should be nil, got %v", mm)
+	}
+}
+
+func TestChipIDStorage(t *testing.T) {
+	// The sysfs tree is created at runtime because the PCI and SCSI addresses contain a ':'.
+	root := t.TempDir()
+	for _, dir := range []string{
+		"bus/scsi",
+		"class/nvme",
+		"devices/pci0000:00/0000:00:1d.0/0000:01:00.0/nvme/nvme0/hwmon1",
+		"devices/pci0000:00/0000:00:17.0/ata2/host1/target1:0:0/1:0:0:0/hwmon/hwmon2",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for target, name := range map[string]string{
+		"../../../../../../class/nvme":  "devices/pci0000:00/0000:00:1d.0/0000:01:00.0/nvme/nvme0/subsystem",
+		"..":                            "devices/pci0000:00/0000:00:1d.0/0000:01:00.0/nvme/nvme0/hwmon1/device",
+		"../../../../../../../bus/scsi": "devices/pci0000:00/0000:00:17.0/ata2/host1/target1:0:0/1:0:0:0/subsystem",
+		"../..":                         "devices/pci0000:00/0000:00:17.0/ata2/host1/target1:0:0/1:0:0:0/hwmon/hwmon2/device",
+	} {
+		if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		dir         string
+		name        string
+		wantID      string
+		wantAdapter string
+	}{
+		{dir: "devices/pci0000:00/0000:00:1d.0/0000:01:00.0/nvme/nvme0/hwmon1", name: "nvme", wantID: "nvme-pci-0100", wantAdapter: "PCI adapter"},
+		{dir: "devices/pci0000:00/0000:00:17.0/ata2/host1/target1:0:0/1:0:0:0/hwmon/hwmon2", name: "drivetemp", wantID: "drivetemp-scsi-1-0", wantAdapter: "SCSI adapter"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.wantID, func(t *testing.T) {
+			t.Parallel()
+
+			id, adapter := chipID(filepath.Join(root, tc.dir), tc.name)
+			if id != tc.wantID || adapter != tc.wantAdapter {
+				t.Errorf("expected: (%s, %s), got: (%s, %s)", tc.wantID, tc.wantAdapter, id, adapter)
+			}
+		})
+	}
+}
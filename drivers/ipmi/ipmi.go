@@ -0,0 +1,248 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipmi reads the sensors of the BMC with 'ipmitool sdr elist full'.
+//
+// Every line of the output is a sensor with its name, record id, status, entity and reading:
+//
+//	Inlet Temp       | 04h | ok  |  7.1 | 23 degrees C
+//	Fan3             | 32h | ns  |  7.1 | No Reading
+//
+// The entity "7.1" is the first instance of entity id 7, the system board. The sensors of each entity are reported as one device.
+package ipmi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultInterface is the ipmitool interface used to reach a remote BMC.
+	DefaultInterface = "lanplus"
+	// PasswordEnv is the environment variable ipmitool reads the password of a remote BMC from.
+	PasswordEnv = "IPMI_PASSWORD"
+)
+
+// Options selects the BMC to read.
+type Options struct {
+	// Host is the address of a remote BMC, the BMC of this machine is read if it is empty.
+	// The password is read by ipmitool from the IPMI_PASSWORD environment variable.
+	Host string
+	// User is the user of the remote BMC.
+	User string
+	// Interface is the ipmitool interface used for a remote BMC, it defaults to "lanplus".
+	Interface string
+	// Name is the name of the machine in the metrics, it defaults to Host or the hostname of this machine.
+	Name string
+	// Command is the ipmitool binary, it defaults to "ipmitool" from the PATH.
+	Command string
+}
+
+// New creates a driver that reads the BMC selected by opts.
+func New(opts Options) common.Driver {
+	if opts.Interface == "" {
+		opts.Interface = DefaultInterface
+	}
+	if opts.Command == "" {
+		opts.Command = "ipmitool"
+	}
+	return &ipmiDriver{
+		opts: opts,
+	}
+}
+
+// Probe returns an error if ipmitool is not installed.
+func Probe() error {
+	if _, err := exec.LookPath("ipmitool"); err != nil {
+		return fmt.Errorf("cannot find the 'ipmitool' command, install ipmitool, err= %w", err)
+	}
+	return nil
+}
+
+type ipmiDriver struct {
+	opts Options
+}
+
+func (d *ipmiDriver) Get() (*pb.MachineMetrics, error) {
+	return d.GetContext(context.Background())
+}
+
+// GetContext kills ipmitool when ctx is done, a remote BMC that does not answer can take a long time to time out.
+func (d *ipmiDriver) GetContext(ctx context.Context) (*pb.MachineMetrics, error) {
+	out, err := exec.CommandContext(ctx, d.opts.Command, d.args()...).Output()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("cannot run '%s', it did not finish in time, err= %w", d.opts.Command, ctx.Err())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot run '%s', is the ipmi driver loaded or the BMC reachable?\nout= %s\nerr= %w", d.opts.Command, out, err)
+	}
+
+	name := d.opts.Name
+	if name == "" {
+		name = d.opts.Host
+	}
+	if name == "" {
+		name = common.Hostname()
+	}
+	return &pb.MachineMetrics{
+		Name:      name,
+		Timestamp: timestamppb.Now(),
+		Device:    parseSDR(out),
+	}, nil
+}
+
+func (d *ipmiDriver) args() []string {
+	args := []string{}
+	if d.opts.Host != "" {
+		args = append(args, "-I", d.opts.Interface, "-H", d.opts.Host)
+		if d.opts.User != "" {
+			args = append(args, "-U", d.opts.User)
+		}
+		// -E fails if the variable is not set, BMCs without a password are read without it.
+		if os.Getenv(PasswordEnv) != "" {
+			args = append(args, "-E")
+		}
+	}
+	return append(args, "sdr", "elist", "full")
+}
+
+// units maps the units of ipmitool to the type and unit of a SensorReading.
+var units = map[string]struct {
+	sensorType string
+	unit       string
+}{
+	"degrees C": {sensorType: "temp", unit: "C"},
+	"degrees F": {sensorType: "temp", unit: "C"},
+	"RPM":       {sensorType: "fan", unit: "RPM"},
+	"Volts":     {sensorType: "in", unit: "V"},
+	"Amps":      {sensorType: "curr", unit: "A"},
+	"Watts":     {sensorType: "power", unit: "W"},
+	"Joules":    {sensorType: "energy", unit: "J"},
+	"percent":   {sensorType: "percent", unit: "%"},
+	"CFM":       {sensorType: "airflow", unit: "CFM"},
+}
+
+// parseSDR creates a device for each entity in the output of 'ipmitool sdr elist', in the order they first appear.
+// Sensors without a reading and discrete sensors like "Presence detected" are skipped.
+func parseSDR(out []byte) []*pb.DeviceMetrics {
+	result := []*pb.DeviceMetrics{}
+	entities := map[string]*pb.DeviceMetrics{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "|")
+		if len(fields) != 5 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		label, status, entity := fields[0], fields[2], fields[3]
+		if status == "ns" {
+			continue
+		}
+		reading, ok := parseReading(fields[4])
+		if !ok {
+			continue
+		}
+		reading.Label = label
+		reading.Alarm = status != "ok"
+
+		device, ok := entities[entity]
+		if !ok {
+			device = &pb.DeviceMetrics{
+				Name: entityName(entity),
+				Kind: "sensor",
+				Chip: "ipmi-" + entity,
+			}
+			entities[entity] = device
+			result = append(result, device)
+		}
+		device.Sensor = append(device.Sensor, reading)
+	}
+	return result
+}
+
+// parseReading parses a reading like "23 degrees C" or "0.40 Amps", it returns false for "No Reading" and discrete sensors.
+func parseReading(s string) (*pb.SensorReading, bool) {
+	fields := strings.SplitN(s, " ", 2)
+	if len(fields) != 2 {
+		return nil, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return nil, false
+	}
+	unit := strings.TrimSpace(fields[1])
+	reading := &pb.SensorReading{
+		Type:  "other",
+		Unit:  unit,
+		Value: value,
+	}
+	if u, ok := units[unit]; ok {
+		reading.Type = u.sensorType
+		reading.Unit = u.unit
+	}
+	if unit == "degrees F" {
+		reading.Value = math.Round((value-32)*5/9*100) / 100
+	}
+	return reading, true
+}
+
+// entityNames are the names of the common entity ids of the IPMI specification.
+var entityNames = map[string]string{
+	"3":  "Processor",
+	"4":  "Disk Bay",
+	"7":  "System Board",
+	"8":  "Memory Module",
+	"10": "Power Supply",
+	"11": "Add-in Card",
+	"19": "Power Unit",
+	"23": "System Chassis",
+	"26": "Disk Drive Bay",
+	"29": "Fan",
+	"30": "Cooling Unit",
+	"32": "Memory Device",
+	"55": "Air Inlet",
+	"64": "Air Inlet",
+	"65": "Processor",
+	"66": "Baseboard",
+}
+
+// entityName returns a name like "System Board 1" for the entity "7.1".
+func entityName(entity string) string {
+	id, instance := entity, ""
+	if i := strings.Index(entity, "."); i >= 0 {
+		id, instance = entity[:i], entity[i+1:]
+	}
+	name, ok := entityNames[id]
+	if !ok {
+		name = "Entity " + id
+	}
+	if instance == "" {
+		return name
+	}
+	return name + " " + instance
+}
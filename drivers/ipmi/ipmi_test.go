@@ -0,0 +1,312 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipmi
+
+import (
+	"context"
+	_ "embed"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+var (
+	//go:embed testdata/dell_r740.txt
+	dellR740 []byte
+	//go:embed testdata/supermicro_x11.txt
+	supermicroX11 []byte
+)
+
+func dellR740Devices() []*pb.DeviceMetrics {
+	return []*pb.DeviceMetrics{
+		{
+			Name: "System Board 1",
+			Kind: "sensor",
+			Chip: "ipmi-7.1",
+			Sensor: []*pb.SensorReading{
+				{Label: "Inlet Temp", Type: "temp", Unit: "C", Value: 23},
+				{Label: "Exhaust Temp", Type: "temp", Unit: "C", Value: 31},
+				{Label: "Fan1", Type: "fan", Unit: "RPM", Value: 5880},
+				{Label: "Fan2", Type: "fan", Unit: "RPM", Value: 5760},
+				{Label: "Pwr Consumption", Type: "power", Unit: "W", Value: 168},
+			},
+		},
+		{
+			Name: "Processor 1",
+			Kind: "sensor",
+			Chip: "ipmi-3.1",
+			Sensor: []*pb.SensorReading{
+				{Label: "Temp", Type: "temp", Unit: "C", Value: 40},
+			},
+		},
+		{
+			Name: "Processor 2",
+			Kind: "sensor",
+			Chip: "ipmi-3.2",
+			Sensor: []*pb.SensorReading{
+				{Label: "Temp", Type: "temp", Unit: "C", Value: 38},
+			},
+		},
+		{
+			Name: "Power Supply 1",
+			Kind: "sensor",
+			Chip: "ipmi-10.1",
+			Sensor: []*pb.SensorReading{
+				{Label: "Current 1", Type: "curr", Unit: "A", Value: 0.4},
+				{Label: "Voltage 1", Type: "in", Unit: "V", Value: 230},
+			},
+		},
+		{
+			Name: "Power Supply 2",
+			Kind: "sensor",
+			Chip: "ipmi-10.2",
+			Sensor: []*pb.SensorReading{
+				{Label: "Current 2", Type: "curr", Unit: "A", Value: 0.4},
+				{Label: "Voltage 2", Type: "in", Unit: "V", Value: 232},
+			},
+		},
+	}
+}
+
+func TestParseSDR(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  []*pb.DeviceMetrics
+	}{
+		{name: "empty", input: []byte{}, want: []*pb.DeviceMetrics{}},
+		{name: "Dell R740", input: dellR740, want: dellR740Devices()},
+		{
+			name:  "Supermicro X11",
+			input: supermicroX11,
+			want: []*pb.DeviceMetrics{
+				{
+					Name: "Processor 1",
+					Kind: "sensor",
+					Chip: "ipmi-3.1",
+					Sensor: []*pb.SensorReading{
+						{Label: "CPU1 Temp", Type: "temp", Unit: "C", Value: 45},
+						{Label: "Vcpu1", Type: "in", Unit: "V", Value: 1.8},
+					},
+				},
+				{
+					Name: "Processor 2",
+					Kind: "sensor",
+					Chip: "ipmi-3.2",
+					Sensor: []*pb.SensorReading{
+						{Label: "CPU2 Temp", Type: "temp", Unit: "C", Value: 43},
+					},
+				},
+				{
+					Name: "System Board 1",
+					Kind: "sensor",
+					Chip: "ipmi-7.1",
+					Sensor: []*pb.SensorReading{
+						{Label: "PCH Temp", Type: "temp", Unit: "C", Value: 48},
+						{Label: "System Temp", Type: "temp", Unit: "C", Value: 30},
+					},
+				},
+				{
+					Name: "Memory Device 64",
+					Kind: "sensor",
+					Chip: "ipmi-32.64",
+					Sensor: []*pb.SensorReading{
+						{Label: "P1-DIMMA1 Temp", Type: "temp", Unit: "C", Value: 33},
+					},
+				},
+				{
+					Name: "Fan 1",
+					Kind: "sensor",
+					Chip: "ipmi-29.1",
+					Sensor: []*pb.SensorReading{
+						{Label: "FAN1", Type: "fan", Unit: "RPM", Value: 3400},
+					},
+				},
+				{
+					Name: "Fan 2",
+					Kind: "sensor",
+					Chip: "ipmi-29.2",
+					Sensor: []*pb.SensorReading{
+						{Label: "FAN2", Type: "fan", Unit: "RPM", Value: 300, Alarm: true},
+					},
+				},
+				{
+					Name: "System Board 17",
+					Kind: "sensor",
+					Chip: "ipmi-7.17",
+					Sensor: []*pb.SensorReading{
+						{Label: "12V", Type: "in", Unit: "V", Value: 12.19},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := parseSDR(tc.input)
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("parseSDR() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseReading(t *testing.T) {
+	tests := []struct {
+		input string
+		want  *pb.SensorReading
+	}{
+		{input: "23 degrees C", want: &pb.SensorReading{Type: "temp", Unit: "C", Value: 23}},
+		{input: "95 degrees F", want: &pb.SensorReading{Type: "temp", Unit: "C", Value: 35}},
+		{input: "0.40 Amps", want: &pb.SensorReading{Type: "curr", Unit: "A", Value: 0.4}},
+		{input: "38 percent", want: &pb.SensorReading{Type: "percent", Unit: "%", Value: 38}},
+		{input: "12 unspecified", want: &pb.SensorReading{Type: "other", Unit: "unspecified", Value: 12}},
+		{input: "No Reading"},
+		{input: "Presence detected"},
+		{input: "0x01"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := parseReading(tc.input)
+			if ok != (tc.want != nil) {
+				t.Fatalf("parseReading(%q) ok = %t, want %t", tc.input, ok, tc.want != nil)
+			}
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("parseReading() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// fakeIpmitool writes an ipmitool that records its arguments and runs body, FIXTURE in body is the path of the Dell R740 fixture.
+func fakeIpmitool(t *testing.T, body string) (string, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake ipmitool is a shell script")
+	}
+	dir := t.TempDir()
+	fixture, err := filepath.Abs("testdata/dell_r740.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := filepath.Join(dir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + args + "\n" + strings.ReplaceAll(body, "FIXTURE", fixture) + "\n"
+	command := filepath.Join(dir, "ipmitool")
+	if err := os.WriteFile(command, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return command, args
+}
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		password string
+		wantName string
+		wantArgs string
+	}{
+		{
+			name:     "local",
+			opts:     Options{Name: "r740"},
+			wantName: "r740",
+			wantArgs: "sdr elist full",
+		},
+		{
+			name:     "remote",
+			opts:     Options{Host: "10.0.0.5", User: "root"},
+			password: "calvin",
+			wantName: "10.0.0.5",
+			wantArgs: "-I lanplus -H 10.0.0.5 -U root -E sdr elist full",
+		},
+		{
+			name:     "remote without password",
+			opts:     Options{Host: "bmc.example.com", Interface: "lan"},
+			wantName: "bmc.example.com",
+			wantArgs: "-I lan -H bmc.example.com sdr elist full",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			command, args := fakeIpmitool(t, "cat FIXTURE")
+			t.Setenv(PasswordEnv, tc.password)
+			tc.opts.Command = command
+
+			got, err := New(tc.opts).Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.GetName() != tc.wantName {
+				t.Errorf("expected name %q, got %q", tc.wantName, got.GetName())
+			}
+			if diff := cmp.Diff(dellR740Devices(), got.GetDevice(), protocmp.Transform()); diff != "" {
+				t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+			}
+			gotArgs, err := os.ReadFile(args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantArgs, strings.TrimSpace(string(gotArgs))); diff != "" {
+				t.Errorf("ipmitool arguments mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetError(t *testing.T) {
+	command, _ := fakeIpmitool(t, "echo 'Error: Unable to establish IPMI v2 / RMCP+ session' >&2\nexit 1")
+	if _, err := New(Options{Host: "10.0.0.5", Command: command}).Get(); err == nil {
+		t.Error("expected an error when ipmitool fails")
+	}
+}
+
+func TestGetContextTimeout(t *testing.T) {
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip(err)
+	}
+	// A BMC that does not answer, exec keeps it a single process so killing it closes its output.
+	command, _ := fakeIpmitool(t, "exec "+sleep+" 60")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = New(Options{Command: command}).(common.ContextDriver).GetContext(ctx)
+	if !common.IsTimeout(err) {
+		t.Errorf("GetContext() error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 30*time.Second {
+		t.Errorf("GetContext() took %s, ipmitool should have been killed", elapsed)
+	}
+}
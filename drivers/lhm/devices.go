@@ -0,0 +1,270 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lhm
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+var (
+	// coreRE matches the sensors of a single core like "CPU Core #1", "Core #1" or "P-Core #1".
+	coreRE = regexp.MustCompile(`^(CPU |[PE]-)?Core #\d+$`)
+	// threadRE matches the load of a core or of one of its threads like "CPU Core #1 Thread #2".
+	threadRE = regexp.MustCompile(`^(CPU |[PE]-)?Core #\d+( Thread #\d+)?$`)
+	// ccdRE matches the temperature of an AMD core complex die like "CCD1 (Tdie)" or "CCD #1".
+	ccdRE = regexp.MustCompile(`^CCD ?#?\d+( \(Tdie\))?$`)
+
+	// groupTypes are the sensor types of the group nodes, OpenHardwareMonitor does not send the type of the sensors.
+	groupTypes = map[string]string{
+		"Voltages":     "Voltage",
+		"Currents":     "Current",
+		"Powers":       "Power",
+		"Clocks":       "Clock",
+		"Temperatures": "Temperature",
+		"Load":         "Load",
+		"Frequencies":  "Frequency",
+		"Fans":         "Fan",
+		"Flows":        "Flow",
+		"Controls":     "Control",
+		"Levels":       "Level",
+		"Factors":      "Factor",
+		"Data":         "Data",
+		"Throughput":   "Throughput",
+		"Energy":       "Energy",
+	}
+
+	// readingTypes are the names of the sensor types in SensorReading, the same as hwmon where there is one.
+	readingTypes = map[string]string{
+		"Voltage":     "in",
+		"Current":     "curr",
+		"Power":       "power",
+		"Clock":       "freq",
+		"Temperature": "temp",
+		"Frequency":   "freq",
+		"Fan":         "fan",
+		"Control":     "pwm",
+		"Energy":      "energy",
+	}
+)
+
+// reading is a sensor of the tree with its value in the units of SensorReading.
+type reading struct {
+	// sensorType is the type of sensor as named by LibreHardwareMonitor, like "Temperature" or "Clock".
+	sensorType string
+	label      string
+	unit       string
+	value      float64
+}
+
+// newReading parses a sensor of group, it returns false if the sensor has no value.
+func newReading(sensor *node, group *node) (*reading, bool) {
+	value, unit, ok := parseValue(sensor.Value)
+	if !ok {
+		return nil, false
+	}
+	r := &reading{
+		sensorType: sensor.Type,
+		label:      sensor.Text,
+		unit:       unit,
+		value:      value,
+	}
+	if r.sensorType == "" {
+		r.sensorType = groupTypes[group.Text]
+	}
+	switch unit {
+	case "°C":
+		r.unit = "C"
+	case "°F":
+		r.unit = "C"
+		r.value = math.Round((value-32)*5/9*100) / 100
+	case "MHz":
+		r.unit = "Hz"
+		r.value = value * 1000000
+	}
+	return r, true
+}
+
+// parseValue parses a value like "45.0 °C" or "1,104 V", the decimal separator follows the locale of the remote machine.
+func parseValue(s string) (float64, string, bool) {
+	fields := strings.SplitN(strings.TrimSpace(s), " ", 2)
+	value, err := strconv.ParseFloat(strings.Replace(fields[0], ",", ".", 1), 64)
+	if err != nil {
+		return 0, "", false
+	}
+	unit := ""
+	if len(fields) == 2 {
+		unit = strings.TrimSpace(fields[1])
+	}
+	return value, unit, true
+}
+
+func (r *reading) sensorReading() *pb.SensorReading {
+	readingType, ok := readingTypes[r.sensorType]
+	if !ok {
+		readingType = strings.ToLower(r.sensorType)
+	}
+	return &pb.SensorReading{
+		Label: r.label,
+		Type:  readingType,
+		Unit:  r.unit,
+		Value: r.value,
+	}
+}
+
+// cpuDevice maps the well known sensors of a CPU, the others are kept as readings.
+func cpuDevice(name string, chip string, socket int32, readings []*reading) *pb.DeviceMetrics {
+	cpu := &pb.CpuDeviceMetrics{Socket: socket}
+	other := []*pb.SensorReading{}
+	for _, r := range readings {
+		switch {
+		case r.sensorType == "Temperature" && coreRE.MatchString(r.label):
+			cpu.Temperature = append(cpu.Temperature, r.value)
+		case r.sensorType == "Temperature" && isPackage(r.label):
+			cpu.PackageTemperature = r.value
+		case r.sensorType == "Temperature" && ccdRE.MatchString(r.label):
+			cpu.CcdTemperature = append(cpu.CcdTemperature, r.value)
+		case r.sensorType == "Load" && threadRE.MatchString(r.label):
+			cpu.Load = append(cpu.Load, int32(math.Round(r.value)))
+		case r.sensorType == "Clock" && coreRE.MatchString(r.label):
+			cpu.CoreFrequencyHz = append(cpu.CoreFrequencyHz, r.value)
+		case r.sensorType == "Clock" && r.label == "Bus Speed":
+			cpu.FsbFrequencyMhz = r.value / 1000000
+		case r.sensorType == "Power" && isPackage(r.label):
+			cpu.PackagePowerWatts = r.value
+		case r.sensorType == "Power" && (r.label == "CPU Cores" || r.label == "Cores"):
+			cpu.CorePowerWatts = r.value
+		case r.sensorType == "Power" && (r.label == "CPU Memory" || r.label == "Memory"):
+			cpu.DramPowerWatts = r.value
+		default:
+			other = append(other, r.sensorReading())
+		}
+	}
+
+	cpu.NumCores = int32(len(cpu.Temperature))
+	if n := int32(len(cpu.CoreFrequencyHz)); n > cpu.NumCores {
+		cpu.NumCores = n
+	}
+	if len(cpu.CoreFrequencyHz) > 0 {
+		cpu.FrequencyMhz = common.Average(cpu.CoreFrequencyHz) / 1000000
+	}
+
+	d := &pb.DeviceMetrics{
+		Name: name,
+		Kind: "cpu",
+		Chip: chip,
+		Cpu:  cpu,
+	}
+	d.Temperature = common.Average(cpu.Temperature)
+	if len(cpu.Temperature) == 0 {
+		d.Temperature = cpu.PackageTemperature
+	}
+	if len(other) > 0 {
+		d.Sensor = other
+	}
+	return d
+}
+
+// isPackage returns true for the sensors of the whole CPU package, "Core (Tctl/Tdie)" is the package temperature of AMD CPUs.
+func isPackage(label string) bool {
+	switch label {
+	case "CPU Package", "Package", "Core (Tctl/Tdie)", "Core (Tctl)":
+		return true
+	}
+	return false
+}
+
+// storageDevice uses the first temperature of a drive as its temperature, the others are the additional sensors.
+func storageDevice(name string, chip string, readings []*reading) *pb.DeviceMetrics {
+	storage := &pb.StorageDeviceMetrics{}
+	d := &pb.DeviceMetrics{
+		Name:    name,
+		Kind:    "storage",
+		Chip:    chip,
+		Storage: storage,
+	}
+	first := true
+	for _, r := range readings {
+		switch {
+		case r.sensorType == "Temperature" && first:
+			d.Temperature = r.value
+			first = false
+		case r.sensorType == "Temperature":
+			storage.SensorTemperature = append(storage.SensorTemperature, r.value)
+		default:
+			d.Sensor = append(d.Sensor, r.sensorReading())
+		}
+	}
+	return d
+}
+
+// sensorDevices creates a device for each fan and voltage rail, like the hwmon driver, and one device of kind with the other readings.
+// The control of a fan with the same label is its PWM duty cycle.
+func sensorDevices(name string, chip string, kind string, readings []*reading) []*pb.DeviceMetrics {
+	controls := map[string]float64{}
+	fans := map[string]bool{}
+	for _, r := range readings {
+		switch r.sensorType {
+		case "Control":
+			controls[r.label] = r.value
+		case "Fan":
+			fans[r.label] = true
+		}
+	}
+
+	result := []*pb.DeviceMetrics{}
+	d := &pb.DeviceMetrics{
+		Name: name,
+		Kind: kind,
+		Chip: chip,
+	}
+	for _, r := range readings {
+		switch r.sensorType {
+		case "Fan":
+			result = append(result, &pb.DeviceMetrics{
+				Name: name,
+				Kind: "fan",
+				Chip: chip,
+				Fan:  &pb.FanDeviceMetrics{Label: r.label, Rpm: r.value, PwmPercent: controls[r.label]},
+			})
+		case "Voltage":
+			result = append(result, &pb.DeviceMetrics{
+				Name:    name,
+				Kind:    "voltage",
+				Chip:    chip,
+				Voltage: &pb.VoltageDeviceMetrics{Label: r.label, Volts: r.value},
+			})
+		case "Control":
+			if fans[r.label] {
+				continue
+			}
+			d.Sensor = append(d.Sensor, r.sensorReading())
+		default:
+			if r.sensorType == "Temperature" && kind == "gpu" && d.Temperature == 0 {
+				d.Temperature = r.value
+			}
+			d.Sensor = append(d.Sensor, r.sensorReading())
+		}
+	}
+	if len(d.Sensor) > 0 {
+		result = append(result, d)
+	}
+	return result
+}
@@ -0,0 +1,236 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lhm reads the sensors of LibreHardwareMonitor or OpenHardwareMonitor on another machine through their remote web server.
+//
+// The web server serves the sensor tree at /data.json. The root has one node per computer, each computer has a node per hardware, each hardware has a node per sensor type and those have the sensors:
+//
+//	Sensor > GAMING-PC > Intel Core i7-12700K > Temperatures > CPU Package = "49.0 °C"
+//
+// LibreHardwareMonitor also sends the HardwareId of the hardware and the SensorId and Type of the sensors, OpenHardwareMonitor only sends the text and the icons.
+package lhm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultPort is the port the remote web server listens on.
+	DefaultPort = "8085"
+	dataPath    = "/data.json"
+)
+
+// Options controls how the web server is read.
+type Options struct {
+	// Name is the name of the remote machine in the metrics, it defaults to the computer name in the sensor tree.
+	Name string
+	// Timeout is how long a request can take, it defaults to 5s.
+	Timeout time.Duration
+	// Client sends the requests, it defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// New creates a driver that reads the web server of LibreHardwareMonitor at address.
+// The address can be a host like "gaming-pc", a host and port like "gaming-pc:8085" or the full URL of the sensor tree like "http://gaming-pc:8085/data.json".
+func New(address string, opts Options) common.Driver {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	return &lhmDriver{
+		url:  dataURL(address),
+		opts: opts,
+	}
+}
+
+// dataURL returns the URL of the sensor tree of address.
+func dataURL(address string) string {
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return address
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), DefaultPort)
+	}
+	if !strings.HasSuffix(u.Path, ".json") {
+		u.Path = strings.TrimSuffix(u.Path, "/") + dataPath
+	}
+	return u.String()
+}
+
+type lhmDriver struct {
+	url  string
+	opts Options
+}
+
+func (d *lhmDriver) Get() (*pb.MachineMetrics, error) {
+	return d.GetContext(context.Background())
+}
+
+func (d *lhmDriver) GetContext(ctx context.Context) (*pb.MachineMetrics, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request for '%s', err= %w", d.url, err)
+	}
+	resp, err := d.opts.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read LibreHardwareMonitor '%s', err= %w", d.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot read LibreHardwareMonitor '%s', status= %s", d.url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read LibreHardwareMonitor '%s', err= %w", d.url, err)
+	}
+
+	mm, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse sensor tree from '%s', err= %w", d.url, err)
+	}
+	if d.opts.Name != "" {
+		mm.Name = d.opts.Name
+	}
+	mm.Timestamp = timestamppb.Now()
+	return mm, nil
+}
+
+// node is a node of the sensor tree, the same type is used for computers, hardware, sensor types and sensors.
+type node struct {
+	Text       string  `json:"Text"`
+	Value      string  `json:"Value"`
+	ImageURL   string  `json:"ImageURL"`
+	HardwareID string  `json:"HardwareId"`
+	SensorID   string  `json:"SensorId"`
+	Type       string  `json:"Type"`
+	Children   []*node `json:"Children"`
+}
+
+// isGroup returns true if the node only has sensors, like "Temperatures" or "Fans".
+func (n *node) isGroup() bool {
+	if len(n.Children) == 0 {
+		return false
+	}
+	for _, child := range n.Children {
+		if len(child.Children) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func parse(data []byte) (*pb.MachineMetrics, error) {
+	root := &node{}
+	if err := json.Unmarshal(data, root); err != nil {
+		return nil, err
+	}
+	if len(root.Children) == 0 {
+		return nil, fmt.Errorf("the sensor tree does not have a computer")
+	}
+	computer := root.Children[0]
+
+	w := &walker{}
+	for _, hw := range computer.Children {
+		w.walk(hw)
+	}
+	return &pb.MachineMetrics{
+		Name:   computer.Text,
+		Device: w.devices,
+	}, nil
+}
+
+// walker converts every hardware of the tree to devices.
+type walker struct {
+	devices []*pb.DeviceMetrics
+	sockets int32
+}
+
+// walk converts hw and its sub hardware, like the Super I/O chip of a motherboard.
+func (w *walker) walk(hw *node) {
+	readings := []*reading{}
+	for _, child := range hw.Children {
+		if !child.isGroup() {
+			w.walk(child)
+			continue
+		}
+		for _, sensor := range child.Children {
+			if r, ok := newReading(sensor, child); ok {
+				readings = append(readings, r)
+			}
+		}
+	}
+	if len(readings) == 0 {
+		return
+	}
+
+	chip := hw.HardwareID
+	if chip == "" {
+		chip = hw.Text
+	}
+	switch kind := hardwareKind(hw); kind {
+	case "cpu":
+		w.devices = append(w.devices, cpuDevice(hw.Text, chip, w.sockets, readings))
+		w.sockets++
+	case "storage":
+		w.devices = append(w.devices, storageDevice(hw.Text, chip, readings))
+	default:
+		w.devices = append(w.devices, sensorDevices(hw.Text, chip, kind, readings)...)
+	}
+}
+
+// hardwareKind returns the kind of device for the hardware, from its HardwareId like "/amdcpu/0" or from its icon for OpenHardwareMonitor.
+func hardwareKind(hw *node) string {
+	id := strings.SplitN(strings.TrimPrefix(hw.HardwareID, "/"), "/", 2)[0]
+	switch {
+	case id == "intelcpu" || id == "amdcpu":
+		return "cpu"
+	case strings.HasPrefix(id, "gpu") || strings.HasSuffix(id, "gpu"):
+		return "gpu"
+	case id == "hdd" || id == "nvme" || id == "ssd":
+		return "storage"
+	case id != "":
+		return "sensor"
+	}
+	icon := hw.ImageURL[strings.LastIndex(hw.ImageURL, "/")+1:]
+	switch icon {
+	case "cpu.png":
+		return "cpu"
+	case "nvidia.png", "ati.png", "amd.png", "intel.png":
+		return "gpu"
+	case "hdd.png", "nvme.png", "ssd.png":
+		return "storage"
+	}
+	return "sensor"
+}
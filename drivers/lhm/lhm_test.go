@@ -0,0 +1,276 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lhm
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+var (
+	//go:embed testdata/lhm.json
+	lhmJSON []byte
+	//go:embed testdata/ohm.json
+	ohmJSON []byte
+)
+
+func lhmMetrics() *pb.MachineMetrics {
+	return &pb.MachineMetrics{
+		Name: "GAMING-PC",
+		Device: []*pb.DeviceMetrics{
+			{Name: "Nuvoton NCT6798D", Kind: "voltage", Chip: "/lpc/nct6798d/0", Voltage: &pb.VoltageDeviceMetrics{Label: "Vcore", Volts: 1.104}},
+			{Name: "Nuvoton NCT6798D", Kind: "voltage", Chip: "/lpc/nct6798d/0", Voltage: &pb.VoltageDeviceMetrics{Label: "+12V", Volts: 12.192}},
+			{Name: "Nuvoton NCT6798D", Kind: "fan", Chip: "/lpc/nct6798d/0", Fan: &pb.FanDeviceMetrics{Label: "CPU Fan", Rpm: 1205, PwmPercent: 45.1}},
+			{Name: "Nuvoton NCT6798D", Kind: "fan", Chip: "/lpc/nct6798d/0", Fan: &pb.FanDeviceMetrics{Label: "Chassis Fan #1"}},
+			{
+				Name: "Nuvoton NCT6798D",
+				Kind: "sensor",
+				Chip: "/lpc/nct6798d/0",
+				Sensor: []*pb.SensorReading{
+					{Label: "Motherboard", Type: "temp", Unit: "C", Value: 32},
+					{Label: "CPU", Type: "temp", Unit: "C", Value: 41.5},
+				},
+			},
+			{
+				Name:        "Intel Core i7-12700K",
+				Kind:        "cpu",
+				Chip:        "/intelcpu/0",
+				Temperature: 46,
+				Cpu: &pb.CpuDeviceMetrics{
+					Load:               []int32{10, 5, 20, 15},
+					Temperature:        []float64{45, 47},
+					NumCores:           2,
+					FrequencyMhz:       4650,
+					FsbFrequencyMhz:    100,
+					CoreFrequencyHz:    []float64{4700000000, 4600000000},
+					PackageTemperature: 49,
+					PackagePowerWatts:  35.2,
+					CorePowerWatts:     28.1,
+					DramPowerWatts:     1.5,
+				},
+				Sensor: []*pb.SensorReading{
+					{Label: "Core Max", Type: "temp", Unit: "C", Value: 47},
+					{Label: "CPU Total", Type: "load", Unit: "%", Value: 12.5},
+				},
+			},
+			{Name: "NVIDIA GeForce RTX 3080", Kind: "fan", Chip: "/gpu-nvidia/0", Fan: &pb.FanDeviceMetrics{Label: "GPU Fan 1", Rpm: 1100}},
+			{
+				Name:        "NVIDIA GeForce RTX 3080",
+				Kind:        "gpu",
+				Chip:        "/gpu-nvidia/0",
+				Temperature: 52,
+				Sensor: []*pb.SensorReading{
+					{Label: "GPU Core", Type: "temp", Unit: "C", Value: 52},
+					{Label: "GPU Hot Spot", Type: "temp", Unit: "C", Value: 63.5},
+					{Label: "GPU Core", Type: "load", Unit: "%", Value: 30},
+					{Label: "GPU Package", Type: "power", Unit: "W", Value: 120.5},
+				},
+			},
+			{
+				Name:        "Samsung SSD 980 PRO 1TB",
+				Kind:        "storage",
+				Chip:        "/nvme/0",
+				Temperature: 44,
+				Storage:     &pb.StorageDeviceMetrics{SensorTemperature: []float64{50}},
+				Sensor: []*pb.SensorReading{
+					{Label: "Used Space", Type: "load", Unit: "%", Value: 61.3},
+				},
+			},
+			{
+				Name: "Generic Memory",
+				Kind: "sensor",
+				Chip: "/ram",
+				Sensor: []*pb.SensorReading{
+					{Label: "Memory", Type: "load", Unit: "%", Value: 42},
+					{Label: "Memory Used", Type: "data", Unit: "GB", Value: 13.4},
+				},
+			},
+		},
+	}
+}
+
+func ohmMetrics() *pb.MachineMetrics {
+	return &pb.MachineMetrics{
+		Name: "OLD-PC",
+		Device: []*pb.DeviceMetrics{
+			{
+				Name:        "Intel Core i5-4590",
+				Kind:        "cpu",
+				Chip:        "Intel Core i5-4590",
+				Temperature: 41,
+				Cpu: &pb.CpuDeviceMetrics{
+					Load:               []int32{10, 5},
+					Temperature:        []float64{40, 42},
+					NumCores:           2,
+					FrequencyMhz:       3293.4,
+					FsbFrequencyMhz:    99.8,
+					CoreFrequencyHz:    []float64{3293400000, 3293400000},
+					PackageTemperature: 43,
+				},
+				Sensor: []*pb.SensorReading{
+					{Label: "CPU Total", Type: "load", Unit: "%", Value: 7.5},
+				},
+			},
+			{
+				Name:        "WDC WD10EZEX-08WN4A0",
+				Kind:        "storage",
+				Chip:        "WDC WD10EZEX-08WN4A0",
+				Temperature: 35,
+				Storage:     &pb.StorageDeviceMetrics{},
+			},
+		},
+	}
+}
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		opts Options
+		want func() *pb.MachineMetrics
+	}{
+		{name: "LibreHardwareMonitor", data: lhmJSON, want: lhmMetrics},
+		{name: "OpenHardwareMonitor in Fahrenheit", data: ohmJSON, want: ohmMetrics},
+		{
+			name: "name",
+			data: ohmJSON,
+			opts: Options{Name: "old-pc.example.com"},
+			want: func() *pb.MachineMetrics {
+				mm := ohmMetrics()
+				mm.Name = "old-pc.example.com"
+				return mm
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/data.json" {
+					http.NotFound(w, r)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(tc.data)
+			}))
+			t.Cleanup(srv.Close)
+
+			got, err := New(srv.URL, tc.opts).Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.GetTimestamp() == nil {
+				t.Error("expected a timestamp")
+			}
+			got.Timestamp = nil
+			if diff := cmp.Diff(tc.want(), got, protocmp.Transform()); diff != "" {
+				t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/broken/data.json":
+			w.Write([]byte("<html>"))
+		case "/slow/data.json":
+			<-r.Context().Done()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	if _, err := New(srv.URL+"/missing", Options{}).Get(); err == nil {
+		t.Error("expected an error for a missing sensor tree")
+	}
+	if _, err := New(srv.URL+"/broken", Options{}).Get(); err == nil {
+		t.Error("expected an error for a broken sensor tree")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := New(srv.URL+"/slow", Options{}).(common.ContextDriver).GetContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %s, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestDataURL(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "gaming-pc", want: "http://gaming-pc:8085/data.json"},
+		{input: "gaming-pc:9000", want: "http://gaming-pc:9000/data.json"},
+		{input: "http://gaming-pc:8085/", want: "http://gaming-pc:8085/data.json"},
+		{input: "https://gaming-pc/lhm", want: "https://gaming-pc:8085/lhm/data.json"},
+		{input: "http://gaming-pc:8085/data.json", want: "http://gaming-pc:8085/data.json"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			if got := dataURL(tc.input); got != tc.want {
+				t.Errorf("dataURL(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		input string
+		value float64
+		unit  string
+		ok    bool
+	}{
+		{input: "45.0 °C", value: 45, unit: "°C", ok: true},
+		{input: "1,104 V", value: 1.104, unit: "V", ok: true},
+		{input: "1205 RPM", value: 1205, unit: "RPM", ok: true},
+		{input: "12.3 MB/s", value: 12.3, unit: "MB/s", ok: true},
+		{input: "", ok: false},
+		{input: "-", ok: false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			value, unit, ok := parseValue(tc.input)
+			if value != tc.value || unit != tc.unit || ok != tc.ok {
+				t.Errorf("parseValue(%q) = (%f, %q, %t), want (%f, %q, %t)", tc.input, value, unit, ok, tc.value, tc.unit, tc.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"fmt"
+)
+
+// PCIChipID returns the libsensors name of a chip on a PCI device like "0000:01:00.0", for example "nvme-pci-0100".
+func PCIChipID(name string, deviceName string) (string, bool) {
+	var domain, bus, slot, fn int
+	if _, err := fmt.Sscanf(deviceName, "%x:%x:%x.%x", &domain, &bus, &slot, &fn); err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s-pci-%04x", name, (domain<<16)+(bus<<8)+(slot<<3)+fn), true
+}
+
+// SCSIChipID returns the libsensors name of a chip on a SCSI device like "1:0:0:0", for example "drivetemp-scsi-1-0".
+func SCSIChipID(name string, deviceName string) (string, bool) {
+	var host, channel, id, lun int
+	if _, err := fmt.Sscanf(deviceName, "%d:%d:%d:%x", &host, &channel, &id, &lun); err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s-scsi-%d-%x", name, host, (channel<<8)+(id<<4)+lun), true
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cpuPackage holds the sensor readings of one physical CPU package (socket).
+type cpuPackage struct {
+	Socket             int32
+	Temperatures       []float64
+	PackageTemperature float64
+	CCDTemperatures    []float64
+	TemperatureMax     []float64
+	TemperatureCrit    []float64
+	TemperatureAlarm   []bool
+}
+
+// HasCPUTemperatures returns true if one of the chips is the temperature sensor of a CPU, like coretemp or k10temp.
+// It only looks at the chips, not their readings, so it can be called before the sensors are read.
+func HasCPUTemperatures(chips []*Chip) bool {
+	for _, chip := range chips {
+		switch chip.Prefix() {
+		case "coretemp", "k10temp", "zenpower":
+			if len(chip.Features) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cpuPackages groups the CPU temperature chips by socket.
+func cpuPackages(chips []*Chip) map[int32]*cpuPackage {
+	all := map[int32]*cpuPackage{}
+	amdSocket := int32(0)
+	for _, chip := range chips {
+		var pkg *cpuPackage
+		switch chip.Prefix() {
+		case "coretemp":
+			pkg = parseCoretemp(chip)
+		case "k10temp", "zenpower":
+			// AMD chips are named after their PCI address so they are numbered in the order they are found instead.
+			pkg = parseAMD(chip, amdSocket)
+			amdSocket++
+		default:
+			continue
+		}
+		if existing, ok := all[pkg.Socket]; ok {
+			existing.Temperatures = append(existing.Temperatures, pkg.Temperatures...)
+			existing.CCDTemperatures = append(existing.CCDTemperatures, pkg.CCDTemperatures...)
+			existing.TemperatureMax = append(existing.TemperatureMax, pkg.TemperatureMax...)
+			existing.TemperatureCrit = append(existing.TemperatureCrit, pkg.TemperatureCrit...)
+			existing.TemperatureAlarm = append(existing.TemperatureAlarm, pkg.TemperatureAlarm...)
+			if existing.PackageTemperature == 0 {
+				existing.PackageTemperature = pkg.PackageTemperature
+			}
+		} else {
+			all[pkg.Socket] = pkg
+		}
+	}
+	return all
+}
+
+// parseCoretemp reads an Intel coretemp chip. There is one chip per socket and the
+// "Package id N" sensor carries the physical id of the socket.
+func parseCoretemp(chip *Chip) *cpuPackage {
+	pkg := &cpuPackage{
+		Socket:           chipIndex(chip),
+		Temperatures:     []float64{},
+		TemperatureMax:   []float64{},
+		TemperatureCrit:  []float64{},
+		TemperatureAlarm: []bool{},
+	}
+	for _, label := range chip.Labels() {
+		if strings.HasPrefix(label, "Package id ") {
+			if id, err := strconv.Atoi(strings.TrimPrefix(label, "Package id ")); err == nil {
+				pkg.Socket = int32(id)
+			}
+			pkg.PackageTemperature, _ = chip.Features[label].Input()
+			continue
+		}
+		feature := chip.Features[label]
+		if value, ok := feature.Input(); ok {
+			maxTemp, _ := feature.Get("max")
+			critTemp, _ := feature.Get("crit")
+			pkg.Temperatures = append(pkg.Temperatures, value)
+			pkg.TemperatureMax = append(pkg.TemperatureMax, maxTemp)
+			pkg.TemperatureCrit = append(pkg.TemperatureCrit, critTemp)
+			pkg.TemperatureAlarm = append(pkg.TemperatureAlarm, feature.Alarm())
+		}
+	}
+	return pkg
+}
+
+// parseAMD reads an AMD k10temp or zenpower chip. These do not report a temperature per core.
+// Tctl is the control temperature that the CPU throttles on, on older kernels Tdie is the same without the offset of some models.
+// Zen 2 and newer also report Tccd1 to Tccd8, one for each core complex die.
+func parseAMD(chip *Chip, socket int32) *cpuPackage {
+	pkg := &cpuPackage{
+		Socket:          socket,
+		Temperatures:    []float64{},
+		CCDTemperatures: []float64{},
+	}
+	tdie := float64(0)
+	for _, label := range chip.Labels() {
+		value, ok := chip.Features[label].Input()
+		if !ok {
+			continue
+		}
+		switch {
+		case label == "Tctl":
+			pkg.PackageTemperature = value
+		case label == "Tdie":
+			tdie = value
+		case strings.HasPrefix(label, "Tccd"):
+			pkg.CCDTemperatures = append(pkg.CCDTemperatures, value)
+		}
+	}
+	if pkg.PackageTemperature == 0 {
+		pkg.PackageTemperature = tdie
+	}
+	return pkg
+}
+
+// chipIndex returns the address of the chip, for example 1 for "coretemp-isa-0001".
+func chipIndex(chip *Chip) int32 {
+	i := strings.LastIndex(chip.ID, "-")
+	if i < 0 {
+		return 0
+	}
+	index, err := strconv.ParseInt(chip.ID[i+1:], 16, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(index)
+}
+
+// naturalLess orders labels like "Core 2" before "Core 10".
+func naturalLess(a string, b string) bool {
+	prefixA, numA, okA := splitTrailingNumber(a)
+	prefixB, numB, okB := splitTrailingNumber(b)
+	if okA && okB && prefixA == prefixB {
+		return numA < numB
+	}
+	return a < b
+}
+
+func splitTrailingNumber(s string) (string, int, bool) {
+	i := len(s)
+	for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+		i--
+	}
+	if i == len(s) {
+		return s, 0, false
+	}
+	num, err := strconv.Atoi(s[i:])
+	if err != nil {
+		return s, 0, false
+	}
+	return s[:i], num, true
+}
+
+func sortLabels(labels []string) {
+	sort.Slice(labels, func(i, j int) bool {
+		return naturalLess(labels[i], labels[j])
+	})
+}
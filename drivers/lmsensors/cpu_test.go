@@ -0,0 +1,164 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestChipLabels(t *testing.T) {
+	chip := &Chip{
+		ID: "coretemp-isa-0000",
+		Features: map[string]Feature{
+			"Core 10":      {},
+			"Core 2":       {},
+			"Core 0":       {},
+			"Package id 0": {},
+		},
+	}
+	want := []string{"Core 0", "Core 2", "Core 10", "Package id 0"}
+	if diff := cmp.Diff(want, chip.Labels()); diff != "" {
+		t.Errorf("Chip.Labels() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestChipIndex(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int32
+	}{
+		{input: "coretemp-isa-0000", want: 0},
+		{input: "coretemp-isa-0001", want: 1},
+		{input: "coretemp-isa-000a", want: 10},
+		{input: "coretemp", want: 0},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			if got := chipIndex(&Chip{ID: tc.input}); got != tc.want {
+				t.Errorf("expected: %d, got: %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseAMD(t *testing.T) {
+	tests := []struct {
+		name string
+		chip *Chip
+		want *cpuPackage
+	}{
+		{
+			name: "Tctl",
+			chip: &Chip{
+				ID: "k10temp-pci-00c3",
+				Features: map[string]Feature{
+					"Tctl":   {"temp1_input": 70},
+					"Tdie":   {"temp2_input": 50},
+					"Tccd10": {"temp12_input": 44},
+					"Tccd2":  {"temp4_input": 42},
+					"Tccd1":  {"temp3_input": 41},
+				},
+			},
+			want: &cpuPackage{
+				Socket:             1,
+				Temperatures:       []float64{},
+				PackageTemperature: 70,
+				CCDTemperatures:    []float64{41, 42, 44},
+			},
+		},
+		{
+			name: "Tdie only",
+			chip: &Chip{
+				ID: "k10temp-pci-00c3",
+				Features: map[string]Feature{
+					"Tdie": {"temp2_input": 50},
+				},
+			},
+			want: &cpuPackage{
+				Socket:             1,
+				Temperatures:       []float64{},
+				PackageTemperature: 50,
+				CCDTemperatures:    []float64{},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.want, parseAMD(tc.chip, 1)); diff != "" {
+				t.Errorf("parseAMD() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseCoretempThresholds(t *testing.T) {
+	chip := &Chip{
+		ID: "coretemp-isa-0000",
+		Features: map[string]Feature{
+			"Package id 0": {"temp1_input": 99, "temp1_max": 80, "temp1_crit": 100, "temp1_crit_alarm": 0},
+			"Core 0":       {"temp2_input": 60, "temp2_max": 80, "temp2_crit": 100, "temp2_crit_alarm": 0},
+			"Core 1":       {"temp3_input": 100, "temp3_max": 80, "temp3_crit": 100, "temp3_crit_alarm": 1},
+			"Core 2":       {"temp4_input": 50},
+		},
+	}
+	want := &cpuPackage{
+		Socket:             0,
+		Temperatures:       []float64{60, 100, 50},
+		PackageTemperature: 99,
+		TemperatureMax:     []float64{80, 80, 0},
+		TemperatureCrit:    []float64{100, 100, 0},
+		TemperatureAlarm:   []bool{false, true, false},
+	}
+	if diff := cmp.Diff(want, parseCoretemp(chip)); diff != "" {
+		t.Errorf("parseCoretemp() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHasCPUTemperatures(t *testing.T) {
+	temp := Feature{"temp1_input": 45}
+	tests := []struct {
+		name  string
+		chips []*Chip
+		want  bool
+	}{
+		{name: "coretemp", chips: []*Chip{{ID: "coretemp-isa-0000", Features: map[string]Feature{"Core 0": temp}}}, want: true},
+		{name: "k10temp", chips: []*Chip{{ID: "nvme-pci-0100", Features: map[string]Feature{"Composite": temp}}, {ID: "k10temp-pci-00c3", Features: map[string]Feature{"Tctl": temp}}}, want: true},
+		{name: "zenpower", chips: []*Chip{{ID: "zenpower-pci-00c3", Features: map[string]Feature{"Tdie": temp}}}, want: true},
+		{name: "chip without sensors", chips: []*Chip{{ID: "coretemp-isa-0000", Features: map[string]Feature{}}}, want: false},
+		{name: "other chips", chips: []*Chip{{ID: "cpu_thermal-virtual-0", Features: map[string]Feature{"temp1": temp}}}, want: false},
+		{name: "no chips", want: false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := HasCPUTemperatures(tc.chips); got != tc.want {
+				t.Errorf("HasCPUTemperatures() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	cpuSysfsDir = "devices/system/cpu"
+)
+
+// cpuFrequency is the clock frequency of a logical CPU in Hz.
+type cpuFrequency struct {
+	CPU       int
+	CurrentHz float64
+	MinHz     float64
+	MaxHz     float64
+}
+
+// readCPUFrequencies reads the frequency of each logical CPU from cpufreq.
+// CPUs without cpufreq, like most virtual machines, fall back to the "cpu MHz" in /proc/cpuinfo.
+func readCPUFrequencies(sysfsRoot string, cpuInfo []*ProcCPUInfo) []*cpuFrequency {
+	all := map[int]*cpuFrequency{}
+	for _, info := range cpuInfo {
+		all[info.Processor] = &cpuFrequency{
+			CPU:       info.Processor,
+			CurrentHz: info.FrequencyMhz * 1000 * 1000,
+		}
+	}
+
+	cpuDirs, _ := filepath.Glob(filepath.Join(sysfsRoot, cpuSysfsDir, "cpu[0-9]*"))
+	for _, cpuDir := range cpuDirs {
+		cpu, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(cpuDir), "cpu"))
+		if err != nil {
+			continue
+		}
+		cur, ok := readKHz(filepath.Join(cpuDir, "cpufreq", "scaling_cur_freq"))
+		if !ok {
+			continue
+		}
+		freq, ok := all[cpu]
+		if !ok {
+			freq = &cpuFrequency{
+				CPU: cpu,
+			}
+			all[cpu] = freq
+		}
+		freq.CurrentHz = cur
+		freq.MinHz, _ = readKHz(filepath.Join(cpuDir, "cpufreq", "scaling_min_freq"))
+		freq.MaxHz, _ = readKHz(filepath.Join(cpuDir, "cpufreq", "scaling_max_freq"))
+	}
+
+	result := []*cpuFrequency{}
+	for _, freq := range all {
+		result = append(result, freq)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CPU < result[j].CPU
+	})
+	return result
+}
+
+// readKHz reads a cpufreq file, which is expressed in kHz, and returns the value in Hz.
+func readKHz(name string) (float64, bool) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return 0, false
+	}
+	khz, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil {
+		return 0, false
+	}
+	return khz * 1000, true
+}
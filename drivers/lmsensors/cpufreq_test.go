@@ -0,0 +1,63 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadCPUFrequencies(t *testing.T) {
+	cpuInfo, err := parseCPUInfo(cpuInfoTXT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		sysfsRoot string
+		want      []*cpuFrequency
+	}{
+		{
+			name:      "cpufreq",
+			sysfsRoot: "testdata/sys",
+			want: []*cpuFrequency{
+				{CPU: 0, CurrentHz: 1600000000, MinHz: 480000000, MaxHz: 2160000000},
+				{CPU: 1, CurrentHz: 2080000000, MinHz: 480000000, MaxHz: 2160000000},
+			},
+		},
+		{
+			name:      "cpuinfo fallback",
+			sysfsRoot: t.TempDir(),
+			want: []*cpuFrequency{
+				{CPU: 0, CurrentHz: 480000000},
+				{CPU: 1, CurrentHz: 790727000},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := readCPUFrequencies(tc.sysfsRoot, cpuInfo)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("readCPUFrequencies() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
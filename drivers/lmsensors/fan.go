@@ -0,0 +1,52 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+// fanDevices returns a device for each fan, like the "fan1" of a nct6775 or thinkpad chip.
+// The device is named after the chip so fans with the same label on different chips can be told apart.
+func fanDevices(chips []*Chip) []*pb.DeviceMetrics {
+	devices := []*pb.DeviceMetrics{}
+	for _, chip := range chips {
+		for _, label := range chip.Labels() {
+			feature := chip.Features[label]
+			if feature.Type() != "fan" {
+				continue
+			}
+			rpm, ok := feature.Input()
+			if !ok {
+				continue
+			}
+			minRpm, _ := feature.Get("min")
+			pwm, _ := feature.Get("pwm")
+			devices = append(devices, &pb.DeviceMetrics{
+				Name: chip.ID,
+				Kind: "fan",
+				Chip: chip.ID,
+				Fan: &pb.FanDeviceMetrics{
+					Label:      label,
+					Rpm:        rpm,
+					MinRpm:     minRpm,
+					Alarm:      feature.Alarm(),
+					PwmPercent: pwm,
+				},
+			})
+		}
+	}
+	return devices
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestFanDevices(t *testing.T) {
+	chips := []*Chip{
+		{
+			ID: "it8728-isa-0a30",
+			Features: map[string]Feature{
+				"fan2":      {"fan2_input": 900, "fan2_min": 0, "fan2_alarm": 0},
+				"CPU Fan":   {"fan1_input": 1500, "fan1_min": 600, "fan1_alarm": 0, "fan1_pwm": 40},
+				"temp1":     {"temp1_input": 40},
+				"intrusion": {"intrusion0_alarm": 1},
+			},
+		},
+		{
+			ID: "thinkpad-isa-0000",
+			Features: map[string]Feature{
+				"fan1": {"fan1_input": 2389},
+				// A fan that is not connected has no reading.
+				"fan2": {"fan2_min": 300},
+			},
+		},
+	}
+	want := []*pb.DeviceMetrics{
+		{
+			Name: "it8728-isa-0a30",
+			Kind: "fan",
+			Chip: "it8728-isa-0a30",
+			Fan:  &pb.FanDeviceMetrics{Label: "CPU Fan", Rpm: 1500, MinRpm: 600, PwmPercent: 40},
+		},
+		{
+			Name: "it8728-isa-0a30",
+			Kind: "fan",
+			Chip: "it8728-isa-0a30",
+			Fan:  &pb.FanDeviceMetrics{Label: "fan2", Rpm: 900},
+		},
+		{
+			Name: "thinkpad-isa-0000",
+			Kind: "fan",
+			Chip: "thinkpad-isa-0000",
+			Fan:  &pb.FanDeviceMetrics{Label: "fan1", Rpm: 2389},
+		},
+	}
+	if diff := cmp.Diff(want, fanDevices(chips), protocmp.Transform()); diff != "" {
+		t.Errorf("fanDevices() mismatch (-want +got):\n%s", diff)
+	}
+}
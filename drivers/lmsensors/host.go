@@ -0,0 +1,232 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultProcRoot is where procfs is mounted on Linux.
+	DefaultProcRoot = "/proc"
+	// DefaultSysfsRoot is where sysfs is mounted on Linux.
+	DefaultSysfsRoot = "/sys"
+)
+
+// Host combines sensor chips with the CPU details that are not part of lm-sensors, like /proc/cpuinfo, /proc/stat and cpufreq.
+// Host keeps the previous sample of /proc/stat and the RAPL energy counters to compute the load and power so a driver should use the same Host for every call to Get().
+type Host struct {
+	procRoot  string
+	sysfsRoot string
+	now       func() time.Time
+
+	mu         sync.Mutex
+	lastStat   map[int]cpuTimes
+	lastEnergy map[string]*energyCounter
+}
+
+// NewHost creates a Host that reads from /proc and /sys.
+func NewHost() *Host {
+	return NewHostWithRoot(DefaultProcRoot, DefaultSysfsRoot)
+}
+
+// NewHostWithRoot creates a Host that reads from procfs and sysfs trees mounted at procRoot and sysfsRoot.
+func NewHostWithRoot(procRoot string, sysfsRoot string) *Host {
+	return &Host{
+		procRoot:   procRoot,
+		sysfsRoot:  sysfsRoot,
+		now:        time.Now,
+		lastEnergy: map[string]*energyCounter{},
+	}
+}
+
+// Metrics converts the chips reported by lm-sensors, or read directly from hwmon, into MachineMetrics.
+// There is one device for each physical CPU package, followed by one device for each fan, voltage rail and drive.
+// The readings of every other chip are reported as generic sensor devices.
+func (h *Host) Metrics(chips []*Chip) *pb.MachineMetrics {
+	cpuInfo, err := readCPUInfo(h.procRoot)
+	if err != nil {
+		cpuInfo = []*ProcCPUInfo{}
+	}
+
+	devices := map[int32]*pb.DeviceMetrics{}
+	device := func(socket int32) *pb.DeviceMetrics {
+		d, ok := devices[socket]
+		if !ok {
+			d = &pb.DeviceMetrics{
+				Name: "Unknown CPU",
+				Kind: "cpu",
+				Cpu: &pb.CpuDeviceMetrics{
+					Load:        []int32{},
+					Temperature: []float64{},
+					Socket:      socket,
+				},
+			}
+			devices[socket] = d
+		}
+		return d
+	}
+
+	cpuSocket := map[int]int32{}
+	coreIds := map[int32]map[string]bool{}
+	for _, info := range cpuInfo {
+		socket := info.Socket()
+		cpuSocket[info.Processor] = socket
+		d := device(socket)
+		if info.CPUName != "" {
+			d.Name = info.CPUName
+		}
+		if coreIds[socket] == nil {
+			coreIds[socket] = map[string]bool{}
+		}
+		coreID := info.CoreId
+		if coreID == "" {
+			// ARM CPUs, like the Raspberry Pi, do not have a "core id" so every processor is a core.
+			coreID = strconv.Itoa(info.Processor)
+		}
+		coreIds[socket][coreID] = true
+	}
+
+	for socket, pkg := range cpuPackages(chips) {
+		cpuMetrics := device(socket).Cpu
+		cpuMetrics.Temperature = pkg.Temperatures
+		cpuMetrics.PackageTemperature = pkg.PackageTemperature
+		cpuMetrics.CcdTemperature = pkg.CCDTemperatures
+		cpuMetrics.TemperatureMax = pkg.TemperatureMax
+		cpuMetrics.TemperatureCrit = pkg.TemperatureCrit
+		cpuMetrics.TemperatureAlarm = pkg.TemperatureAlarm
+	}
+
+	for _, load := range h.load() {
+		cpuMetrics := device(cpuSocket[load.CPU]).Cpu
+		cpuMetrics.Load = append(cpuMetrics.Load, load.Total)
+		cpuMetrics.LoadUser = append(cpuMetrics.LoadUser, load.User)
+		cpuMetrics.LoadSystem = append(cpuMetrics.LoadSystem, load.System)
+		cpuMetrics.LoadIowait = append(cpuMetrics.LoadIowait, load.Iowait)
+		cpuMetrics.LoadSteal = append(cpuMetrics.LoadSteal, load.Steal)
+	}
+	for _, freq := range readCPUFrequencies(h.sysfsRoot, cpuInfo) {
+		cpuMetrics := device(cpuSocket[freq.CPU]).Cpu
+		cpuMetrics.CoreFrequencyHz = append(cpuMetrics.CoreFrequencyHz, freq.CurrentHz)
+		cpuMetrics.CoreFrequencyMinHz = append(cpuMetrics.CoreFrequencyMinHz, freq.MinHz)
+		cpuMetrics.CoreFrequencyMaxHz = append(cpuMetrics.CoreFrequencyMaxHz, freq.MaxHz)
+	}
+
+	for _, energy := range h.power() {
+		cpuMetrics := device(energy.Socket).Cpu
+		switch energy.Domain {
+		case "package":
+			cpuMetrics.PackagePowerWatts = energy.Watts
+			cpuMetrics.PackageEnergyJoules = energy.Joules
+		case "core":
+			cpuMetrics.CorePowerWatts = energy.Watts
+			cpuMetrics.CoreEnergyJoules = energy.Joules
+		case "dram":
+			cpuMetrics.DramPowerWatts = energy.Watts
+			cpuMetrics.DramEnergyJoules = energy.Joules
+		}
+	}
+
+	if len(devices) == 0 {
+		device(0)
+	}
+
+	mm := &pb.MachineMetrics{
+		Name:      common.Hostname(),
+		Timestamp: timestamppb.Now(),
+		Device:    []*pb.DeviceMetrics{},
+	}
+	for socket, d := range devices {
+		cpuMetrics := d.Cpu
+		d.Temperature = common.Average(cpuMetrics.Temperature)
+		if len(cpuMetrics.Temperature) == 0 {
+			// AMD CPUs do not report a temperature per core.
+			d.Temperature = cpuMetrics.PackageTemperature
+		}
+		cpuMetrics.NumCores = int32(len(cpuMetrics.Temperature))
+		if cpuMetrics.NumCores == 0 {
+			cpuMetrics.NumCores = int32(len(coreIds[socket]))
+		}
+		cpuMetrics.FrequencyMhz = common.Average(cpuMetrics.CoreFrequencyHz) / 1000 / 1000
+		mm.Device = append(mm.Device, d)
+	}
+	sort.Slice(mm.Device, func(i, j int) bool {
+		return mm.Device[i].GetCpu().GetSocket() < mm.Device[j].GetCpu().GetSocket()
+	})
+	mm.Device = append(mm.Device, fanDevices(chips)...)
+	mm.Device = append(mm.Device, voltageDevices(chips)...)
+	mm.Device = append(mm.Device, storageDevices(chips, readStorageNames(h.sysfsRoot))...)
+	mm.Device = append(mm.Device, sensorDevices(chips)...)
+	return mm
+}
+
+// load returns the load of each logical CPU since the previous call. The first call has nothing to compare against and returns no load.
+func (h *Host) load() []*cpuLoad {
+	cur, err := readProcStat(h.procRoot)
+	if err != nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	prev := h.lastStat
+	h.lastStat = cur
+	if prev == nil {
+		return nil
+	}
+	return computeLoad(prev, cur)
+}
+
+// power returns the power of each RAPL zone since the previous call. The first call has nothing to compare against and reports no power.
+func (h *Host) power() []*raplEnergy {
+	zones := readRAPL(h.sysfsRoot)
+	now := h.now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	result := []*raplEnergy{}
+	for _, zone := range zones {
+		energy := &raplEnergy{
+			Socket: zone.Socket,
+			Domain: zone.Domain,
+		}
+		result = append(result, energy)
+
+		counter, ok := h.lastEnergy[zone.Name]
+		if !ok {
+			h.lastEnergy[zone.Name] = &energyCounter{
+				EnergyUJ: zone.EnergyUJ,
+				Time:     now,
+			}
+			continue
+		}
+		joules := float64(energyDelta(counter.EnergyUJ, zone.EnergyUJ, zone.MaxEnergyUJ)) / 1000 / 1000
+		if elapsed := now.Sub(counter.Time).Seconds(); elapsed > 0 {
+			energy.Watts = joules / elapsed
+		}
+		counter.EnergyUJ = zone.EnergyUJ
+		counter.Time = now
+		counter.Joules += joules
+		energy.Joules = counter.Joules
+	}
+	return result
+}
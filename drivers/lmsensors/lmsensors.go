@@ -19,20 +19,23 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/jeremyje/coretemp-exporter/drivers/common"
 	pb "github.com/jeremyje/coretemp-exporter/proto"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func New() common.Driver {
 	return newDriver()
 }
 
+// Probe returns an error that explains why lm-sensors cannot be run on this machine, or nil if it can.
+func Probe() error {
+	return probe()
+}
+
 func fromJSON(out []byte) (*lmsensorData, error) {
 	m := map[string]any{}
 	if err := json.Unmarshal(sanitizeSensorData(out), &m); err != nil {
@@ -59,6 +62,41 @@ type lmsensorData struct {
 	M map[string]any
 }
 
+func (d *lmsensorData) chips() []*Chip {
+	chips := []*Chip{}
+	for sensorID, sensorDetail := range d.M {
+		concreteSensorDetail, ok := sensorDetail.(map[string]any)
+		if !ok {
+			continue
+		}
+		chip := &Chip{
+			ID:       sensorID,
+			Features: map[string]Feature{},
+		}
+		if adapterName, ok := concreteSensorDetail["Adapter"].(string); ok {
+			chip.Adapter = adapterName
+		}
+		for detailName, maybeDetail := range concreteSensorDetail {
+			concreteDetail, ok := maybeDetail.(map[string]any)
+			if !ok {
+				continue
+			}
+			feature := Feature{}
+			for name, value := range concreteDetail {
+				if s, err := strconv.ParseFloat(fmt.Sprintf("%v", value), 64); err == nil {
+					feature[name] = s
+				}
+			}
+			chip.Features[detailName] = feature
+		}
+		chips = append(chips, chip)
+	}
+	sort.Slice(chips, func(i, j int) bool {
+		return chips[i].ID < chips[j].ID
+	})
+	return chips
+}
+
 type LMsensor struct {
 	Adapter      string `json:"Adapter"`
 	Temperatures map[string]*LMSensorTemperature
@@ -68,76 +106,74 @@ type LMSensorTemperature struct {
 	M map[string]float64
 }
 
-func parseLmsensorsOutput(out []byte) (*pb.MachineMetrics, error) {
-	data, err := fromJSON(out)
-	if err != nil {
-		return nil, err
+// Chip is a single sensor chip, the top level object in the output of 'sensors -j'.
+type Chip struct {
+	// ID is the name of the chip, for example "coretemp-isa-0000".
+	ID string
+	// Adapter is the bus that the chip is attached to, for example "ISA adapter".
+	Adapter string
+	// Features are the sensors of the chip keyed by their label, for example "Core 0".
+	Features map[string]Feature
+}
+
+// Prefix is the driver name of the chip, for example "coretemp" for "coretemp-isa-0000".
+func (c *Chip) Prefix() string {
+	prefix, _, _ := strings.Cut(c.ID, "-")
+	return prefix
+}
+
+// Labels returns the labels of the features in natural order, "Core 2" comes before "Core 10".
+func (c *Chip) Labels() []string {
+	labels := []string{}
+	for label := range c.Features {
+		labels = append(labels, label)
 	}
+	sortLabels(labels)
+	return labels
+}
 
-	cpuName := "Unknown CPU"
-	frequency := float64(0.0)
+// Feature holds the subfeature values of a sensor keyed by their name, for example "temp2_input".
+type Feature map[string]float64
 
-	// cpuInfo should not be read outside of this scope.
-	{
-		cpuInfo, err := readCPUInfo()
-		if err == nil {
-			cpuName = cpuInfo.CPUName
-			frequency = cpuInfo.FrequencyMhz
+// Input returns the current reading of the feature, the value of "tempN_input", "fanN_input", etc.
+func (f Feature) Input() (float64, bool) {
+	return f.Get("input")
+}
+
+// Type returns the kind of sensor, "temp", "fan", "in", etc., which prefixes the name of every subfeature.
+func (f Feature) Type() string {
+	for name := range f {
+		if i := strings.Index(name, "_"); i >= 0 {
+			return strings.TrimRight(name[:i], "0123456789")
 		}
 	}
+	return ""
+}
 
-	temperatures := []float64{}
-	load := []int32{}
-	for sensorID, sensorDetail := range data.M {
-		if strings.Contains(sensorID, "coretemp") {
-			concreteSensorDetail, ok := sensorDetail.(map[string]any)
-			if ok {
-				adapterName := concreteSensorDetail["Adapter"]
-				if adapterName == "" {
-					adapterName = sensorID
-				}
-				keys := []string{}
-				for detailName := range concreteSensorDetail {
-					if strings.Contains(detailName, "Package") {
-						continue
-					}
-					keys = append(keys, detailName)
-				}
-				sort.Strings(keys)
-				for _, detailName := range keys {
-					maybeTempDetail := concreteSensorDetail[detailName]
-					if concreteTempDetail, ok := maybeTempDetail.(map[string]any); ok {
-						for name, value := range concreteTempDetail {
-							if strings.Contains(name, "input") {
-								if s, err := strconv.ParseFloat(fmt.Sprintf("%v", value), 64); err == nil {
-									temperatures = append(temperatures, s)
-								}
-							}
-						}
-					}
-				}
-			}
+// Alarm returns true if any of the alarms of the feature are raised, like "temp2_crit_alarm".
+func (f Feature) Alarm() bool {
+	for name, value := range f {
+		if strings.HasSuffix(name, "alarm") && value != 0 {
+			return true
 		}
 	}
+	return false
+}
+
+// Get returns the subfeature with the given suffix, for example Get("crit") returns the value of "temp2_crit".
+func (f Feature) Get(suffix string) (float64, bool) {
+	for name, value := range f {
+		if i := strings.Index(name, "_"); i >= 0 && name[i+1:] == suffix {
+			return value, true
+		}
+	}
+	return 0, false
+}
 
-	hostname, err := os.Hostname()
+func parseLmsensorsOutput(host *Host, out []byte) (*pb.MachineMetrics, error) {
+	data, err := fromJSON(out)
 	if err != nil {
-		hostname = "unknown"
+		return nil, err
 	}
-	return &pb.MachineMetrics{
-		Name:      hostname,
-		Timestamp: timestamppb.Now(),
-		Device: []*pb.DeviceMetrics{
-			{
-				Name:        cpuName,
-				Kind:        "cpu",
-				Temperature: common.Average(temperatures),
-				Cpu: &pb.CpuDeviceMetrics{
-					Load:         load,
-					Temperature:  temperatures,
-					NumCores:     int32(len(temperatures)),
-					FrequencyMhz: frequency,
-				},
-			}},
-	}, nil
+	return host.Metrics(data.chips()), nil
 }
@@ -18,6 +18,7 @@
 package lmsensors
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 
@@ -26,16 +27,34 @@ import (
 )
 
 type lmsensorsDriver struct {
+	host *Host
 }
 
 func (d *lmsensorsDriver) Get() (*pb.MachineMetrics, error) {
-	out, err := exec.Command("sensors", "-j").Output()
+	return d.GetContext(context.Background())
+}
+
+// GetContext kills 'sensors' when ctx is done, it can hang forever on a broken i2c bus.
+func (d *lmsensorsDriver) GetContext(ctx context.Context) (*pb.MachineMetrics, error) {
+	out, err := exec.CommandContext(ctx, "sensors", "-j").Output()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("cannot run 'sensors' command, it did not finish in time, err= %w", ctx.Err())
+	}
 	if err != nil {
 		return nil, fmt.Errorf("cannot run 'sensors' command, is it installed or running in a VM?\nout= %s\nerr= %w", out, err)
 	}
-	return parseLmsensorsOutput(out)
+	return parseLmsensorsOutput(d.host, out)
+}
+
+func probe() error {
+	if _, err := exec.LookPath("sensors"); err != nil {
+		return fmt.Errorf("cannot find the 'sensors' command, install lm-sensors, err= %w", err)
+	}
+	return nil
 }
 
 func newDriver() common.Driver {
-	return &lmsensorsDriver{}
+	return &lmsensorsDriver{
+		host: NewHost(),
+	}
 }
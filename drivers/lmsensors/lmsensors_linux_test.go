@@ -0,0 +1,54 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package lmsensors
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+)
+
+func TestGetContextTimeout(t *testing.T) {
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip(err)
+	}
+	// A 'sensors' that hangs like it does on a broken i2c bus. exec keeps it a single process so killing it closes its output.
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sensors"), []byte("#!/bin/sh\nexec "+sleep+" 60\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+
+	d := &lmsensorsDriver{host: NewHostWithRoot(t.TempDir(), t.TempDir())}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = d.GetContext(ctx)
+	if !common.IsTimeout(err) {
+		t.Errorf("GetContext() error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 30*time.Second {
+		t.Errorf("GetContext() took %s, 'sensors' should have been killed", elapsed)
+	}
+}
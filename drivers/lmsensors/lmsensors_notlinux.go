@@ -30,3 +30,7 @@ var (
 func newDriver() common.Driver {
 	return common.NotSupported(errNotSupported)
 }
+
+func probe() error {
+	return errNotSupported
+}
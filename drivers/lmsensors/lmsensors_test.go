@@ -17,6 +17,8 @@ package lmsensors
 import (
 	_ "embed"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -29,6 +31,18 @@ var (
 	sensorsJSON []byte
 	//go:embed testdata/sensors_nuc2.json
 	sensorsNuc2JSON []byte
+	//go:embed testdata/sensors_k10temp.json
+	sensorsK10tempJSON []byte
+	//go:embed testdata/sensors_zenpower.json
+	sensorsZenpowerJSON []byte
+	//go:embed testdata/sensors_nct6775.json
+	sensorsNct6775JSON []byte
+	//go:embed testdata/sensors_thinkpad.json
+	sensorsThinkpadJSON []byte
+	//go:embed testdata/sensors_2s.json
+	sensors2sJSON []byte
+	//go:embed testdata/proc_cpuinfo_2s.txt
+	cpuInfo2sTXT []byte
 )
 
 func ExampleNew() {
@@ -65,8 +79,18 @@ func TestParseLmsensorsOutput(t *testing.T) {
 						Kind:        "cpu",
 						Temperature: 36.5,
 						Cpu: &pb.CpuDeviceMetrics{
-							NumCores:    2,
-							Temperature: []float64{30, 43},
+							NumCores:         2,
+							Temperature:      []float64{30, 43},
+							TemperatureMax:   []float64{90, 90},
+							TemperatureCrit:  []float64{90, 90},
+							TemperatureAlarm: []bool{false, false},
+						},
+					},
+					{
+						Kind: "sensor",
+						Chip: "acpitz-acpi-0",
+						Sensor: []*pb.SensorReading{
+							{Label: "temp1", Type: "temp", Unit: "C", Value: 40, Crit: 90},
 						},
 					},
 				},
@@ -82,8 +106,169 @@ func TestParseLmsensorsOutput(t *testing.T) {
 						Kind:        "cpu",
 						Temperature: 45,
 						Cpu: &pb.CpuDeviceMetrics{
-							NumCores:    2,
-							Temperature: []float64{45, 45},
+							NumCores:           2,
+							Temperature:        []float64{45, 45},
+							PackageTemperature: 45,
+							TemperatureMax:     []float64{105, 105},
+							TemperatureCrit:    []float64{105, 105},
+							TemperatureAlarm:   []bool{false, false},
+						},
+					},
+					{
+						Kind: "sensor",
+						Chip: "acpitz-acpi-0",
+						Sensor: []*pb.SensorReading{
+							{Label: "temp1", Type: "temp", Unit: "C", Value: 44, Crit: 95},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "sensors_k10temp.json",
+			input: sensorsK10tempJSON,
+			want: &pb.MachineMetrics{
+				Device: []*pb.DeviceMetrics{
+					{
+						Name:        "",
+						Kind:        "cpu",
+						Temperature: 62.125,
+						Cpu: &pb.CpuDeviceMetrics{
+							PackageTemperature: 62.125,
+							CcdTemperature:     []float64{58.25, 55.5},
+						},
+					},
+					{
+						Kind:        "storage",
+						Chip:        "nvme-pci-0100",
+						Temperature: 38.85,
+						Storage: &pb.StorageDeviceMetrics{
+							SensorTemperature:  []float64{},
+							TemperatureWarning: 81.85,
+							TemperatureCrit:    84.85,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "sensors_zenpower.json",
+			input: sensorsZenpowerJSON,
+			want: &pb.MachineMetrics{
+				Device: []*pb.DeviceMetrics{
+					{
+						Name:        "",
+						Kind:        "cpu",
+						Temperature: 51.125,
+						Cpu: &pb.CpuDeviceMetrics{
+							PackageTemperature: 51.125,
+							CcdTemperature:     []float64{47, 45.75},
+						},
+					},
+					{
+						Kind:    "voltage",
+						Chip:    "zenpower-pci-00c3",
+						Voltage: &pb.VoltageDeviceMetrics{Label: "SVI2_Core", Volts: 1.369},
+					},
+					{
+						Kind:    "voltage",
+						Chip:    "zenpower-pci-00c3",
+						Voltage: &pb.VoltageDeviceMetrics{Label: "SVI2_SoC", Volts: 1.019},
+					},
+					{
+						Kind: "sensor",
+						Chip: "zenpower-pci-00c3",
+						Sensor: []*pb.SensorReading{
+							{Label: "SVI2_C_Core", Type: "curr", Unit: "A", Value: 17.265},
+							{Label: "SVI2_C_SoC", Type: "curr", Unit: "A", Value: 6.978},
+							{Label: "SVI2_P_Core", Type: "power", Unit: "W", Value: 23.64},
+							{Label: "SVI2_P_SoC", Type: "power", Unit: "W", Value: 7.11},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "sensors_nct6775.json",
+			input: sensorsNct6775JSON,
+			want: &pb.MachineMetrics{
+				Device: []*pb.DeviceMetrics{
+					{
+						Kind:        "cpu",
+						Temperature: 40,
+						Cpu: &pb.CpuDeviceMetrics{
+							NumCores:           2,
+							Temperature:        []float64{39, 41},
+							PackageTemperature: 41,
+							TemperatureMax:     []float64{80, 80},
+							TemperatureCrit:    []float64{100, 100},
+							TemperatureAlarm:   []bool{false, false},
+						},
+					},
+					{
+						Kind: "fan",
+						Chip: "nct6775-isa-0290",
+						Fan:  &pb.FanDeviceMetrics{Label: "fan1"},
+					},
+					{
+						Kind: "fan",
+						Chip: "nct6775-isa-0290",
+						Fan:  &pb.FanDeviceMetrics{Label: "fan2", Rpm: 1146, MinRpm: 300},
+					},
+					{
+						Kind: "fan",
+						Chip: "nct6775-isa-0290",
+						Fan:  &pb.FanDeviceMetrics{Label: "fan3", Rpm: 212, MinRpm: 300, Alarm: true},
+					},
+					{
+						Kind:    "voltage",
+						Chip:    "nct6775-isa-0290",
+						Voltage: &pb.VoltageDeviceMetrics{Label: "in0", Volts: 0.88, MaxVolts: 1.744},
+					},
+					{
+						Kind:    "voltage",
+						Chip:    "nct6775-isa-0290",
+						Voltage: &pb.VoltageDeviceMetrics{Label: "in1", Volts: 1.824, Alarm: true},
+					},
+					{
+						Kind: "sensor",
+						Chip: "nct6775-isa-0290",
+						Sensor: []*pb.SensorReading{
+							{Label: "CPUTIN", Type: "temp", Unit: "C", Value: 36.5, Max: 80},
+							{Label: "SYSTIN", Type: "temp", Unit: "C", Value: 33},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "sensors_thinkpad.json",
+			input: sensorsThinkpadJSON,
+			want: &pb.MachineMetrics{
+				Device: []*pb.DeviceMetrics{
+					{
+						Kind:        "cpu",
+						Temperature: 51,
+						Cpu: &pb.CpuDeviceMetrics{
+							NumCores:           2,
+							Temperature:        []float64{52, 50},
+							PackageTemperature: 53,
+							TemperatureMax:     []float64{100, 100},
+							TemperatureCrit:    []float64{100, 100},
+							TemperatureAlarm:   []bool{false, false},
+						},
+					},
+					{
+						Kind: "fan",
+						Chip: "thinkpad-isa-0000",
+						Fan:  &pb.FanDeviceMetrics{Label: "fan1", Rpm: 2389},
+					},
+					{
+						Kind: "sensor",
+						Chip: "thinkpad-isa-0000",
+						Sensor: []*pb.SensorReading{
+							{Label: "CPU", Type: "temp", Unit: "C", Value: 52},
+							{Label: "GPU", Type: "temp", Unit: "C"},
 						},
 					},
 				},
@@ -96,7 +281,7 @@ func TestParseLmsensorsOutput(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			got, err := parseLmsensorsOutput(tc.input)
+			got, err := parseLmsensorsOutput(NewHostWithRoot(t.TempDir(), t.TempDir()), tc.input)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -106,3 +291,59 @@ func TestParseLmsensorsOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLmsensorsOutputMultiSocket(t *testing.T) {
+	procRoot := t.TempDir()
+	if err := os.WriteFile(filepath.Join(procRoot, cpuinfoFile), cpuInfo2sTXT, 0664); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parseLmsensorsOutput(NewHostWithRoot(procRoot, t.TempDir()), sensors2sJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pb.MachineMetrics{
+		Device: []*pb.DeviceMetrics{
+			{
+				Name:        "Intel(R) Xeon(R) CPU E5-2670 0 @ 2.60GHz",
+				Kind:        "cpu",
+				Temperature: 49,
+				Cpu: &pb.CpuDeviceMetrics{
+					NumCores:           2,
+					Temperature:        []float64{48, 50},
+					PackageTemperature: 52,
+					TemperatureMax:     []float64{82, 82},
+					TemperatureCrit:    []float64{92, 92},
+					TemperatureAlarm:   []bool{false, false},
+					FrequencyMhz:       1250,
+					CoreFrequencyHz:    []float64{1200000000, 1300000000},
+					// Without cpufreq there are no frequency limits.
+					CoreFrequencyMinHz: []float64{0, 0},
+					CoreFrequencyMaxHz: []float64{0, 0},
+					Socket:             0,
+				},
+			},
+			{
+				Name:        "Intel(R) Xeon(R) CPU E5-2670 0 @ 2.60GHz",
+				Kind:        "cpu",
+				Temperature: 59,
+				Cpu: &pb.CpuDeviceMetrics{
+					NumCores:           2,
+					Temperature:        []float64{58, 60},
+					PackageTemperature: 61,
+					TemperatureMax:     []float64{82, 82},
+					TemperatureCrit:    []float64{92, 92},
+					TemperatureAlarm:   []bool{false, false},
+					FrequencyMhz:       1450,
+					CoreFrequencyHz:    []float64{1400000000, 1500000000},
+					CoreFrequencyMinHz: []float64{0, 0},
+					CoreFrequencyMaxHz: []float64{0, 0},
+					Socket:             1,
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform(), protocmp.IgnoreFields(&pb.MachineMetrics{}, "name", "timestamp")); diff != "" {
+		t.Errorf("parseLmsensorsOutput() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	powercapClassDir = "class/powercap"
+)
+
+// raplZone is a reading of the energy counter of a RAPL (Running Average Power Limit) zone, like "intel-rapl:0" or "intel-rapl:0:1".
+type raplZone struct {
+	Name   string
+	Socket int32
+	// Domain is "package", "core", "uncore", "dram" or "psys".
+	Domain      string
+	EnergyUJ    uint64
+	MaxEnergyUJ uint64
+}
+
+// raplEnergy is the power drawn by a RAPL zone since the previous reading and the energy it used since the first reading.
+type raplEnergy struct {
+	Socket int32
+	Domain string
+	Watts  float64
+	Joules float64
+}
+
+// energyCounter is the last reading of a RAPL zone.
+type energyCounter struct {
+	EnergyUJ uint64
+	Time     time.Time
+	Joules   float64
+}
+
+// readRAPL reads the energy counters of the RAPL zones in the powercap class.
+// AMD CPUs also use the intel-rapl control type. Zones that cannot be read, energy_uj is only readable by root on newer kernels, are skipped.
+func readRAPL(sysfsRoot string) []*raplZone {
+	dirs, _ := filepath.Glob(filepath.Join(sysfsRoot, powercapClassDir, "intel-rapl:*"))
+	sort.Strings(dirs)
+
+	zones := []*raplZone{}
+	for _, dir := range dirs {
+		energy, ok := readUint(filepath.Join(dir, "energy_uj"))
+		if !ok {
+			continue
+		}
+		maxEnergy, _ := readUint(filepath.Join(dir, "max_energy_range_uj"))
+		zone := &raplZone{
+			Name:        filepath.Base(dir),
+			EnergyUJ:    energy,
+			MaxEnergyUJ: maxEnergy,
+		}
+		zone.Domain, zone.Socket = raplDomain(readTrimmed(filepath.Join(dir, "name")))
+
+		// Subzones like "intel-rapl:0:1" are named "core" or "dram", the socket comes from the parent zone "intel-rapl:0".
+		if parts := strings.Split(zone.Name, ":"); len(parts) == 3 {
+			_, zone.Socket = raplDomain(readTrimmed(filepath.Join(filepath.Dir(dir), parts[0]+":"+parts[1], "name")))
+		}
+		zones = append(zones, zone)
+	}
+	return zones
+}
+
+// raplDomain splits a zone name like "package-1" into the domain and the socket.
+func raplDomain(name string) (string, int32) {
+	domain, socket, ok := strings.Cut(name, "-")
+	if !ok {
+		return name, 0
+	}
+	id, err := strconv.Atoi(socket)
+	if err != nil {
+		return name, 0
+	}
+	return domain, int32(id)
+}
+
+// energyDelta returns the microjoules used between two readings of a counter that wraps around to 0 after maxRange.
+func energyDelta(prev uint64, cur uint64, maxRange uint64) uint64 {
+	if cur >= prev {
+		return cur - prev
+	}
+	if maxRange < prev {
+		// The counter was reset instead of wrapping around.
+		return 0
+	}
+	return maxRange - prev + cur
+}
+
+func readUint(name string) (uint64, bool) {
+	value, err := strconv.ParseUint(readTrimmed(name), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+func readTrimmed(name string) string {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	testMaxEnergyRangeUJ = 262143328850
+)
+
+// writeRAPLZone creates a powercap zone in a temporary sysfs tree.
+// The zones are not checked into testdata because their names contain a ':'.
+func writeRAPLZone(t *testing.T, sysfsRoot string, zone string, name string, energyUJ uint64) {
+	t.Helper()
+	dir := filepath.Join(sysfsRoot, powercapClassDir, zone)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"name":                name,
+		"energy_uj":           fmt.Sprintf("%d", energyUJ),
+		"max_energy_range_uj": fmt.Sprintf("%d", testMaxEnergyRangeUJ),
+	}
+	for file, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadRAPL(t *testing.T) {
+	sysfsRoot := t.TempDir()
+	writeRAPLZone(t, sysfsRoot, "intel-rapl:0", "package-0", 1000)
+	writeRAPLZone(t, sysfsRoot, "intel-rapl:0:0", "core", 200)
+	writeRAPLZone(t, sysfsRoot, "intel-rapl:0:1", "dram", 300)
+	writeRAPLZone(t, sysfsRoot, "intel-rapl:1", "package-1", 4000)
+	writeRAPLZone(t, sysfsRoot, "intel-rapl:1:0", "core", 500)
+
+	want := []*raplZone{
+		{Name: "intel-rapl:0", Socket: 0, Domain: "package", EnergyUJ: 1000, MaxEnergyUJ: testMaxEnergyRangeUJ},
+		{Name: "intel-rapl:0:0", Socket: 0, Domain: "core", EnergyUJ: 200, MaxEnergyUJ: testMaxEnergyRangeUJ},
+		{Name: "intel-rapl:0:1", Socket: 0, Domain: "dram", EnergyUJ: 300, MaxEnergyUJ: testMaxEnergyRangeUJ},
+		{Name: "intel-rapl:1", Socket: 1, Domain: "package", EnergyUJ: 4000, MaxEnergyUJ: testMaxEnergyRangeUJ},
+		{Name: "intel-rapl:1:0", Socket: 1, Domain: "core", EnergyUJ: 500, MaxEnergyUJ: testMaxEnergyRangeUJ},
+	}
+	if diff := cmp.Diff(want, readRAPL(sysfsRoot)); diff != "" {
+		t.Errorf("readRAPL() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadRAPLMissing(t *testing.T) {
+	if got := readRAPL(t.TempDir()); len(got) != 0 {
+		t.Errorf("expected no zones, got %v", got)
+	}
+}
+
+func TestEnergyDelta(t *testing.T) {
+	tests := []struct {
+		name     string
+		prev     uint64
+		cur      uint64
+		maxRange uint64
+		want     uint64
+	}{
+		{name: "increment", prev: 100, cur: 350, maxRange: 1000, want: 250},
+		{name: "no change", prev: 100, cur: 100, maxRange: 1000, want: 0},
+		{name: "wraparound", prev: 900, cur: 50, maxRange: 1000, want: 150},
+		{name: "reset", prev: 2000, cur: 50, maxRange: 1000, want: 0},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := energyDelta(tc.prev, tc.cur, tc.maxRange); got != tc.want {
+				t.Errorf("expected: %d, got: %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHostPower(t *testing.T) {
+	sysfsRoot := t.TempDir()
+	writeRAPLZone(t, sysfsRoot, "intel-rapl:0", "package-0", testMaxEnergyRangeUJ-10000000)
+	writeRAPLZone(t, sysfsRoot, "intel-rapl:0:0", "core", 1000000)
+	writeRAPLZone(t, sysfsRoot, "intel-rapl:0:2", "dram", 5000000)
+
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	h := NewHostWithRoot(t.TempDir(), sysfsRoot)
+	h.now = func() time.Time {
+		return now
+	}
+
+	want := []*raplEnergy{
+		{Socket: 0, Domain: "package"},
+		{Socket: 0, Domain: "core"},
+		{Socket: 0, Domain: "dram"},
+	}
+	if diff := cmp.Diff(want, h.power()); diff != "" {
+		t.Errorf("power() first call mismatch (-want +got):\n%s", diff)
+	}
+
+	// 2 seconds later the package counter wrapped around after using 30 J.
+	now = now.Add(2 * time.Second)
+	writeRAPLZone(t, sysfsRoot, "intel-rapl:0", "package-0", 20000000)
+	writeRAPLZone(t, sysfsRoot, "intel-rapl:0:0", "core", 21000000)
+	writeRAPLZone(t, sysfsRoot, "intel-rapl:0:2", "dram", 7000000)
+	want = []*raplEnergy{
+		{Socket: 0, Domain: "package", Watts: 15, Joules: 30},
+		{Socket: 0, Domain: "core", Watts: 10, Joules: 20},
+		{Socket: 0, Domain: "dram", Watts: 1, Joules: 2},
+	}
+	if diff := cmp.Diff(want, h.power()); diff != "" {
+		t.Errorf("power() second call mismatch (-want +got):\n%s", diff)
+	}
+
+	now = now.Add(4 * time.Second)
+	writeRAPLZone(t, sysfsRoot, "intel-rapl:0", "package-0", 40000000)
+	metrics := h.Metrics(nil)
+	cpuMetrics := metrics.GetDevice()[0].GetCpu()
+	if cpuMetrics.GetPackagePowerWatts() != 5 {
+		t.Errorf("expected 5 W, got %f", cpuMetrics.GetPackagePowerWatts())
+	}
+	if cpuMetrics.GetPackageEnergyJoules() != 50 {
+		t.Errorf("expected 50 J, got %f", cpuMetrics.GetPackageEnergyJoules())
+	}
+	if cpuMetrics.GetCorePowerWatts() != 0 || cpuMetrics.GetCoreEnergyJoules() != 20 {
+		t.Errorf("expected 0 W and 20 J for the cores, got %f W and %f J", cpuMetrics.GetCorePowerWatts(), cpuMetrics.GetCoreEnergyJoules())
+	}
+}
@@ -19,46 +19,66 @@ import (
 	_ "embed"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
 
 const (
-	cpuinfoFile = "/proc/cpuinfo"
+	cpuinfoFile = "cpuinfo"
 )
 
 type ProcCPUInfo struct {
+	Processor    int     `json:"processor"`
 	VendorId     string  `json:"vendor_id"`
 	CPUName      string  `json:"cpu_name"`
 	CoreId       string  `json:"core_id"`
+	PhysicalId   string  `json:"physical_id"`
 	FrequencyMhz float64 `json:"frequency"`
 }
 
-func readCPUInfo() (*ProcCPUInfo, error) {
-	data, err := os.ReadFile(cpuinfoFile)
+// Socket returns the physical id of the CPU package, or 0 if it is not known.
+func (p *ProcCPUInfo) Socket() int32 {
+	socket, err := strconv.Atoi(p.PhysicalId)
 	if err != nil {
-		return nil, fmt.Errorf("cannot read '%s', err= %w", cpuinfoFile, err)
+		return 0
+	}
+	return int32(socket)
+}
+
+func readCPUInfo(procRoot string) ([]*ProcCPUInfo, error) {
+	name := filepath.Join(procRoot, cpuinfoFile)
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read '%s', err= %w", name, err)
 	}
 	return parseCPUInfo(data)
 }
 
-func parseCPUInfo(consoleOut []byte) (*ProcCPUInfo, error) {
-	all := parseCpuInfoConsoleMaps(consoleOut)
+// parseCPUInfo returns one entry for each processor block in /proc/cpuinfo.
+func parseCPUInfo(consoleOut []byte) ([]*ProcCPUInfo, error) {
+	all := []*ProcCPUInfo{}
 
-	for _, m := range all {
-		freqString := m["cpu MHz"]
-		freq, err := strconv.ParseFloat(freqString, 64)
-		if err == nil {
-			freq *= 1000 * 1000
+	for i, m := range parseCpuInfoConsoleMaps(consoleOut) {
+		processor, err := strconv.Atoi(m["processor"])
+		if err != nil {
+			processor = i
 		}
-		return &ProcCPUInfo{
+		freq, _ := strconv.ParseFloat(m["cpu MHz"], 64)
+		all = append(all, &ProcCPUInfo{
+			Processor:    processor,
 			VendorId:     m["vendor_id"],
 			CPUName:      m["model name"],
+			CoreId:       m["core id"],
+			PhysicalId:   m["physical id"],
 			FrequencyMhz: freq,
-		}, nil
+		})
 	}
 
-	return nil, fmt.Errorf("cannot get CPU information from '%s'", string(consoleOut))
+	if len(all) == 0 {
+		return nil, fmt.Errorf("cannot get CPU information from '%s'", string(consoleOut))
+	}
+	return all, nil
 }
 
 func parseCpuInfoConsoleMaps(consoleOut []byte) []map[string]string {
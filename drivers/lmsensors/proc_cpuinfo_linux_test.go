@@ -23,11 +23,11 @@ import (
 )
 
 func TestReadCPUInfo(t *testing.T) {
-	info, err := readCPUInfo()
+	info, err := readCPUInfo(DefaultProcRoot)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if info.CPUName == "" {
+	if info[0].CPUName == "" {
 		t.Error("CPUName should not be empty")
 	}
 }
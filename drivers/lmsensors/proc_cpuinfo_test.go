@@ -31,7 +31,25 @@ func TestParseCPUInfo(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if diff := cmp.Diff("Intel(R) Celeron(R) CPU  N3050  @ 1.60GHz", data.CPUName); diff != "" {
-		t.Errorf(".CPUName mismatch (-want +got):\n%s", diff)
+	want := []*ProcCPUInfo{
+		{
+			Processor:    0,
+			VendorId:     "GenuineIntel",
+			CPUName:      "Intel(R) Celeron(R) CPU  N3050  @ 1.60GHz",
+			CoreId:       "0",
+			PhysicalId:   "0",
+			FrequencyMhz: 480,
+		},
+		{
+			Processor:    1,
+			VendorId:     "GenuineIntel",
+			CPUName:      "Intel(R) Celeron(R) CPU  N3050  @ 1.60GHz",
+			CoreId:       "2",
+			PhysicalId:   "0",
+			FrequencyMhz: 790.727,
+		},
+	}
+	if diff := cmp.Diff(want, data); diff != "" {
+		t.Errorf("parseCPUInfo() mismatch (-want +got):\n%s", diff)
 	}
 }
@@ -0,0 +1,142 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	statFile = "stat"
+)
+
+// cpuTimes are the jiffies a logical CPU spent in each mode, as reported by a "cpuN" line in /proc/stat.
+type cpuTimes struct {
+	User    uint64
+	Nice    uint64
+	System  uint64
+	Idle    uint64
+	Iowait  uint64
+	Irq     uint64
+	Softirq uint64
+	Steal   uint64
+}
+
+func (c cpuTimes) total() uint64 {
+	return c.User + c.Nice + c.System + c.Idle + c.Iowait + c.Irq + c.Softirq + c.Steal
+}
+
+// cpuLoad is the utilization of a logical CPU between two samples of /proc/stat.
+type cpuLoad struct {
+	CPU    int
+	Total  int32
+	User   float64
+	System float64
+	Iowait float64
+	Steal  float64
+}
+
+func readProcStat(procRoot string) (map[int]cpuTimes, error) {
+	name := filepath.Join(procRoot, statFile)
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read '%s', err= %w", name, err)
+	}
+	return parseProcStat(data)
+}
+
+func parseProcStat(consoleOut []byte) (map[int]cpuTimes, error) {
+	all := map[int]cpuTimes{}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(consoleOut)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// The aggregate "cpu" line is skipped, only "cpuN" lines are per logical CPU.
+		if len(fields) < 9 || !strings.HasPrefix(fields[0], "cpu") || fields[0] == "cpu" {
+			continue
+		}
+		cpu, err := strconv.Atoi(strings.TrimPrefix(fields[0], "cpu"))
+		if err != nil {
+			continue
+		}
+		values := make([]uint64, 8)
+		for i := range values {
+			values[i], err = strconv.ParseUint(fields[i+1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse '%s' in /proc/stat line '%s', err= %w", fields[i+1], scanner.Text(), err)
+			}
+		}
+		all[cpu] = cpuTimes{
+			User:    values[0],
+			Nice:    values[1],
+			System:  values[2],
+			Idle:    values[3],
+			Iowait:  values[4],
+			Irq:     values[5],
+			Softirq: values[6],
+			Steal:   values[7],
+		}
+	}
+
+	if len(all) == 0 {
+		return nil, fmt.Errorf("cannot find per-cpu lines in '%s'", string(consoleOut))
+	}
+	return all, nil
+}
+
+// computeLoad returns the load of each logical CPU, ordered by CPU number, that was spent between prev and cur.
+func computeLoad(prev map[int]cpuTimes, cur map[int]cpuTimes) []*cpuLoad {
+	cpus := []int{}
+	for cpu := range cur {
+		cpus = append(cpus, cpu)
+	}
+	sort.Ints(cpus)
+
+	result := []*cpuLoad{}
+	for _, cpu := range cpus {
+		load := &cpuLoad{
+			CPU: cpu,
+		}
+		result = append(result, load)
+
+		before, ok := prev[cpu]
+		after := cur[cpu]
+		// A CPU that was just brought online or a counter that went backwards has no meaningful delta.
+		if !ok || after.total() <= before.total() {
+			continue
+		}
+		total := float64(after.total() - before.total())
+		percent := func(a uint64, b uint64) float64 {
+			if a < b {
+				return 0
+			}
+			return float64(a-b) * 100 / total
+		}
+		idle := percent(after.Idle+after.Iowait, before.Idle+before.Iowait)
+		load.Total = int32(math.Round(100 - idle))
+		load.User = percent(after.User+after.Nice, before.User+before.Nice)
+		load.System = percent(after.System+after.Irq+after.Softirq, before.System+before.Irq+before.Softirq)
+		load.Iowait = percent(after.Iowait, before.Iowait)
+		load.Steal = percent(after.Steal, before.Steal)
+	}
+	return result
+}
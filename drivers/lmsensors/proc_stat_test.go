@@ -0,0 +1,110 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	_ "embed"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+var (
+	//go:embed testdata/proc_stat.txt
+	procStatTXT []byte
+	//go:embed testdata/proc_stat_next.txt
+	procStatNextTXT []byte
+)
+
+func TestParseProcStat(t *testing.T) {
+	got, err := parseProcStat(procStatTXT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int]cpuTimes{
+		0: {User: 5000, Nice: 100, System: 1500, Idle: 40000, Iowait: 250, Irq: 50, Softirq: 50, Steal: 50},
+		1: {User: 5000, Nice: 100, System: 1500, Idle: 40000, Iowait: 250, Irq: 50, Softirq: 50, Steal: 50},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseProcStat() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseProcStatEmpty(t *testing.T) {
+	if _, err := parseProcStat([]byte("intr 1 2 3\n")); err == nil {
+		t.Error("expected an error when there are no cpu lines")
+	}
+}
+
+func TestComputeLoad(t *testing.T) {
+	prev, err := parseProcStat(procStatTXT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur, err := parseProcStat(procStatNextTXT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*cpuLoad{
+		{CPU: 0, Total: 88, User: 62.5, System: 12.5, Iowait: 0, Steal: 12.5},
+		{CPU: 1, Total: 30, User: 10, System: 10, Iowait: 10, Steal: 10},
+	}
+	if diff := cmp.Diff(want, computeLoad(prev, cur)); diff != "" {
+		t.Errorf("computeLoad() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Without a previous sample or when the counters go backwards the load is 0.
+	want = []*cpuLoad{{CPU: 0}, {CPU: 1}}
+	if diff := cmp.Diff(want, computeLoad(map[int]cpuTimes{}, cur)); diff != "" {
+		t.Errorf("computeLoad() without previous sample mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want, computeLoad(cur, prev)); diff != "" {
+		t.Errorf("computeLoad() with counters going backwards mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHostLoad(t *testing.T) {
+	dir := t.TempDir()
+	statPath := filepath.Join(dir, statFile)
+	host := NewHostWithRoot(dir, dir)
+
+	if err := os.WriteFile(statPath, procStatTXT, 0664); err != nil {
+		t.Fatal(err)
+	}
+	first := host.Metrics([]*Chip{}).GetDevice()[0].GetCpu()
+	if len(first.GetLoad()) != 0 {
+		t.Errorf("the first sample should not have any load, got %v", first.GetLoad())
+	}
+
+	if err := os.WriteFile(statPath, procStatNextTXT, 0664); err != nil {
+		t.Fatal(err)
+	}
+	got := host.Metrics([]*Chip{}).GetDevice()[0].GetCpu()
+	want := &pb.CpuDeviceMetrics{
+		Load:       []int32{88, 30},
+		LoadUser:   []float64{62.5, 10},
+		LoadSystem: []float64{12.5, 10},
+		LoadIowait: []float64{0, 10},
+		LoadSteal:  []float64{12.5, 10},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Host.Metrics() mismatch (-want +got):\n%s", diff)
+	}
+}
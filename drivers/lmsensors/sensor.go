@@ -0,0 +1,92 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+var (
+	// sensorUnits are the units that lm-sensors reports each type of sensor in.
+	sensorUnits = map[string]string{
+		"temp":     "C",
+		"fan":      "RPM",
+		"in":       "V",
+		"power":    "W",
+		"energy":   "J",
+		"curr":     "A",
+		"humidity": "%",
+		"freq":     "Hz",
+	}
+	// temperatureChips are the chips whose temperatures are reported by the cpu and storage devices.
+	temperatureChips = map[string]bool{
+		"coretemp":  true,
+		"k10temp":   true,
+		"zenpower":  true,
+		"nvme":      true,
+		"drivetemp": true,
+	}
+)
+
+// sensorDevices returns a device for each chip with the readings that are not already reported by the cpu, fan, voltage or storage devices.
+// This makes chips like acpitz, jc42, amdgpu or PMBus power supplies visible without specific support for them.
+func sensorDevices(chips []*Chip) []*pb.DeviceMetrics {
+	devices := []*pb.DeviceMetrics{}
+	for _, chip := range chips {
+		readings := []*pb.SensorReading{}
+		for _, label := range chip.Labels() {
+			feature := chip.Features[label]
+			sensorType := feature.Type()
+			switch {
+			case sensorType == "fan" || sensorType == "in":
+				continue
+			case sensorType == "temp" && temperatureChips[chip.Prefix()]:
+				continue
+			}
+
+			value, ok := feature.Input()
+			if !ok {
+				// Power meters like amdgpu only report an average.
+				value, ok = feature.Get("average")
+			}
+			if !ok {
+				continue
+			}
+			minValue, _ := feature.Get("min")
+			maxValue, _ := feature.Get("max")
+			critValue, _ := feature.Get("crit")
+			readings = append(readings, &pb.SensorReading{
+				Label: label,
+				Type:  sensorType,
+				Unit:  sensorUnits[sensorType],
+				Value: value,
+				Min:   minValue,
+				Max:   maxValue,
+				Crit:  critValue,
+				Alarm: feature.Alarm(),
+			})
+		}
+		if len(readings) == 0 {
+			continue
+		}
+		devices = append(devices, &pb.DeviceMetrics{
+			Name:   chip.ID,
+			Kind:   "sensor",
+			Chip:   chip.ID,
+			Sensor: readings,
+		})
+	}
+	return devices
+}
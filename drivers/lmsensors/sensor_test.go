@@ -0,0 +1,80 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestSensorDevices(t *testing.T) {
+	chips := []*Chip{
+		{
+			ID: "amdgpu-pci-0300",
+			Features: map[string]Feature{
+				"edge":   {"temp1_input": 48, "temp1_crit": 100, "temp1_crit_hyst": -273.15},
+				"PPT":    {"power1_average": 31.2, "power1_cap": 203},
+				"fan1":   {"fan1_input": 0, "fan1_min": 0, "fan1_max": 3300},
+				"vddgfx": {"in0_input": 0.8},
+				"sclk":   {"freq1_input": 500000000},
+			},
+		},
+		{
+			ID: "coretemp-isa-0000",
+			Features: map[string]Feature{
+				"Core 0": {"temp2_input": 40},
+			},
+		},
+		{
+			ID: "jc42-i2c-0-18",
+			Features: map[string]Feature{
+				"temp1": {"temp1_input": 36.5, "temp1_max": 85, "temp1_min": 0, "temp1_crit": 95, "temp1_crit_alarm": 1},
+			},
+		},
+		{
+			ID: "iwlwifi_1-virtual-0",
+			Features: map[string]Feature{
+				// Sensors that are not available have no reading.
+				"temp1": {"temp1_type": 0},
+			},
+		},
+	}
+	want := []*pb.DeviceMetrics{
+		{
+			Name: "amdgpu-pci-0300",
+			Kind: "sensor",
+			Chip: "amdgpu-pci-0300",
+			Sensor: []*pb.SensorReading{
+				{Label: "PPT", Type: "power", Unit: "W", Value: 31.2},
+				{Label: "edge", Type: "temp", Unit: "C", Value: 48, Crit: 100},
+				{Label: "sclk", Type: "freq", Unit: "Hz", Value: 500000000},
+			},
+		},
+		{
+			Name: "jc42-i2c-0-18",
+			Kind: "sensor",
+			Chip: "jc42-i2c-0-18",
+			Sensor: []*pb.SensorReading{
+				{Label: "temp1", Type: "temp", Unit: "C", Value: 36.5, Max: 85, Crit: 95, Alarm: true},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, sensorDevices(chips), protocmp.Transform()); diff != "" {
+		t.Errorf("sensorDevices() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"path/filepath"
+
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+const (
+	nvmeClassDir  = "class/nvme"
+	blockClassDir = "class/block"
+)
+
+// storageName identifies the drive behind a nvme or drivetemp chip.
+type storageName struct {
+	Model  string
+	Device string
+}
+
+// readStorageNames finds the model of each NVMe and SATA drive keyed by the name of its chip, for example "nvme-pci-0100" or "drivetemp-scsi-0-0".
+func readStorageNames(sysfsRoot string) map[string]*storageName {
+	names := map[string]*storageName{}
+
+	controllers, _ := filepath.Glob(filepath.Join(sysfsRoot, nvmeClassDir, "nvme[0-9]*"))
+	for _, controller := range controllers {
+		device, err := filepath.EvalSymlinks(filepath.Join(controller, "device"))
+		if err != nil {
+			continue
+		}
+		if id, ok := PCIChipID("nvme", filepath.Base(device)); ok {
+			names[id] = &storageName{
+				Model:  readTrimmed(filepath.Join(controller, "model")),
+				Device: filepath.Base(controller),
+			}
+		}
+	}
+
+	disks, _ := filepath.Glob(filepath.Join(sysfsRoot, blockClassDir, "sd*"))
+	for _, disk := range disks {
+		device, err := filepath.EvalSymlinks(filepath.Join(disk, "device"))
+		if err != nil {
+			continue
+		}
+		if id, ok := SCSIChipID("drivetemp", filepath.Base(device)); ok {
+			names[id] = &storageName{
+				Model:  readTrimmed(filepath.Join(device, "model")),
+				Device: filepath.Base(disk),
+			}
+		}
+	}
+	return names
+}
+
+// storageDevices returns a device for each nvme and drivetemp chip.
+// The temperature of a NVMe drive is the "Composite" sensor, its other sensors are reported separately.
+func storageDevices(chips []*Chip, names map[string]*storageName) []*pb.DeviceMetrics {
+	devices := []*pb.DeviceMetrics{}
+	for _, chip := range chips {
+		if prefix := chip.Prefix(); prefix != "nvme" && prefix != "drivetemp" {
+			continue
+		}
+
+		labels := []string{}
+		for _, label := range chip.Labels() {
+			if _, ok := chip.Features[label].Input(); ok && chip.Features[label].Type() == "temp" {
+				labels = append(labels, label)
+			}
+		}
+		if len(labels) == 0 {
+			continue
+		}
+		composite := labels[0]
+		for _, label := range labels {
+			if label == "Composite" {
+				composite = label
+			}
+		}
+
+		feature := chip.Features[composite]
+		temp, _ := feature.Input()
+		warning, _ := feature.Get("max")
+		crit, _ := feature.Get("crit")
+		storageMetrics := &pb.StorageDeviceMetrics{
+			SensorTemperature:  []float64{},
+			TemperatureWarning: warning,
+			TemperatureCrit:    crit,
+			Alarm:              feature.Alarm(),
+		}
+		for _, label := range labels {
+			if label != composite {
+				value, _ := chip.Features[label].Input()
+				storageMetrics.SensorTemperature = append(storageMetrics.SensorTemperature, value)
+			}
+		}
+
+		name := chip.ID
+		if storage, ok := names[chip.ID]; ok {
+			storageMetrics.Device = storage.Device
+			name = storage.Model
+			if name == "" {
+				name = storage.Device
+			}
+		}
+		devices = append(devices, &pb.DeviceMetrics{
+			Name:        name,
+			Kind:        "storage",
+			Chip:        chip.ID,
+			Temperature: temp,
+			Storage:     storageMetrics,
+		})
+	}
+	return devices
+}
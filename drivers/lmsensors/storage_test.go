@@ -0,0 +1,187 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	_ "embed"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+var (
+	//go:embed testdata/sensors_storage.json
+	sensorsStorageJSON []byte
+)
+
+// writeStorageTree creates the sysfs entries of a NVMe and a SATA drive in a temporary sysfs tree.
+// The tree is not checked into testdata because the PCI and SCSI addresses contain a ':'.
+func writeStorageTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	mkdir := func(dir string) {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(name string, content string) {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	symlink := func(target string, name string) {
+		if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mkdir("devices/pci0000:00/0000:00:1d.0/0000:01:00.0/nvme/nvme0")
+	mkdir("class/nvme")
+	write("devices/pci0000:00/0000:00:1d.0/0000:01:00.0/nvme/nvme0/model", "Samsung SSD 970 EVO Plus 1TB           \n")
+	symlink("../../../0000:01:00.0", "devices/pci0000:00/0000:00:1d.0/0000:01:00.0/nvme/nvme0/device")
+	symlink("../../devices/pci0000:00/0000:00:1d.0/0000:01:00.0/nvme/nvme0", "class/nvme/nvme0")
+
+	mkdir("devices/pci0000:00/0000:00:17.0/ata2/host1/target1:0:0/1:0:0:0/block/sdb")
+	mkdir("class/block")
+	write("devices/pci0000:00/0000:00:17.0/ata2/host1/target1:0:0/1:0:0:0/model", "WDC WD40EFRX-68N\n")
+	symlink("../../../1:0:0:0", "devices/pci0000:00/0000:00:17.0/ata2/host1/target1:0:0/1:0:0:0/block/sdb/device")
+	symlink("../../devices/pci0000:00/0000:00:17.0/ata2/host1/target1:0:0/1:0:0:0/block/sdb", "class/block/sdb")
+	return root
+}
+
+func TestReadStorageNames(t *testing.T) {
+	want := map[string]*storageName{
+		"nvme-pci-0100":      {Model: "Samsung SSD 970 EVO Plus 1TB", Device: "nvme0"},
+		"drivetemp-scsi-1-0": {Model: "WDC WD40EFRX-68N", Device: "sdb"},
+	}
+	if diff := cmp.Diff(want, readStorageNames(writeStorageTree(t))); diff != "" {
+		t.Errorf("readStorageNames() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStorageDevices(t *testing.T) {
+	data, err := fromJSON(sensorsStorageJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		names map[string]*storageName
+		want  []*pb.DeviceMetrics
+	}{
+		{
+			name:  "model",
+			names: readStorageNames(writeStorageTree(t)),
+			want: []*pb.DeviceMetrics{
+				{
+					Name:        "WDC WD40EFRX-68N",
+					Kind:        "storage",
+					Chip:        "drivetemp-scsi-1-0",
+					Temperature: 35,
+					Storage: &pb.StorageDeviceMetrics{
+						Device:             "sdb",
+						SensorTemperature:  []float64{},
+						TemperatureWarning: 60,
+						TemperatureCrit:    70,
+					},
+				},
+				{
+					Name:        "Samsung SSD 970 EVO Plus 1TB",
+					Kind:        "storage",
+					Chip:        "nvme-pci-0100",
+					Temperature: 44.85,
+					Storage: &pb.StorageDeviceMetrics{
+						Device:             "nvme0",
+						SensorTemperature:  []float64{44.85, 51.85},
+						TemperatureWarning: 81.85,
+						TemperatureCrit:    84.85,
+					},
+				},
+			},
+		},
+		{
+			name:  "no sysfs",
+			names: map[string]*storageName{},
+			want: []*pb.DeviceMetrics{
+				{
+					Name:        "drivetemp-scsi-1-0",
+					Kind:        "storage",
+					Chip:        "drivetemp-scsi-1-0",
+					Temperature: 35,
+					Storage: &pb.StorageDeviceMetrics{
+						SensorTemperature:  []float64{},
+						TemperatureWarning: 60,
+						TemperatureCrit:    70,
+					},
+				},
+				{
+					Name:        "nvme-pci-0100",
+					Kind:        "storage",
+					Chip:        "nvme-pci-0100",
+					Temperature: 44.85,
+					Storage: &pb.StorageDeviceMetrics{
+						SensorTemperature:  []float64{44.85, 51.85},
+						TemperatureWarning: 81.85,
+						TemperatureCrit:    84.85,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.want, storageDevices(data.chips(), tc.names), protocmp.Transform()); diff != "" {
+				t.Errorf("storageDevices() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChipIDs(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		wantOk bool
+	}{
+		{input: "0000:01:00.0", want: "nvme-pci-0100", wantOk: true},
+		{input: "0000:00:18.3", want: "nvme-pci-00c3", wantOk: true},
+		{input: "1:0:0:0", want: "drivetemp-scsi-1-0", wantOk: true},
+		{input: "nvme0", wantOk: false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := PCIChipID("nvme", tc.input)
+			if !ok {
+				got, ok = SCSIChipID("drivetemp", tc.input)
+			}
+			if ok != tc.wantOk || got != tc.want {
+				t.Errorf("expected: (%s, %t), got: (%s, %t)", tc.want, tc.wantOk, got, ok)
+			}
+		})
+	}
+}
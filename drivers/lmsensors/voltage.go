@@ -0,0 +1,51 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+// voltageDevices returns a device for each voltage rail, the "inN" features of Super I/O chips like the nct6775 or it87.
+func voltageDevices(chips []*Chip) []*pb.DeviceMetrics {
+	devices := []*pb.DeviceMetrics{}
+	for _, chip := range chips {
+		for _, label := range chip.Labels() {
+			feature := chip.Features[label]
+			if feature.Type() != "in" {
+				continue
+			}
+			volts, ok := feature.Input()
+			if !ok {
+				continue
+			}
+			minVolts, _ := feature.Get("min")
+			maxVolts, _ := feature.Get("max")
+			devices = append(devices, &pb.DeviceMetrics{
+				Name: chip.ID,
+				Kind: "voltage",
+				Chip: chip.ID,
+				Voltage: &pb.VoltageDeviceMetrics{
+					Label:    label,
+					Volts:    volts,
+					MinVolts: minVolts,
+					MaxVolts: maxVolts,
+					Alarm:    feature.Alarm(),
+				},
+			})
+		}
+	}
+	return devices
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lmsensors
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestVoltageDevices(t *testing.T) {
+	chips := []*Chip{
+		{
+			ID: "it8728-isa-0a30",
+			Features: map[string]Feature{
+				"Vcore": {"in0_input": 1.056, "in0_min": 0.9, "in0_max": 1.5, "in0_alarm": 0},
+				"+12V":  {"in2_input": 10.8, "in2_min": 11.4, "in2_max": 12.6, "in2_alarm": 1},
+				"fan1":  {"fan1_input": 1500},
+				// An unused input has no reading.
+				"in3": {"in3_min": 0},
+			},
+		},
+	}
+	want := []*pb.DeviceMetrics{
+		{
+			Name:    "it8728-isa-0a30",
+			Kind:    "voltage",
+			Chip:    "it8728-isa-0a30",
+			Voltage: &pb.VoltageDeviceMetrics{Label: "+12V", Volts: 10.8, MinVolts: 11.4, MaxVolts: 12.6, Alarm: true},
+		},
+		{
+			Name:    "it8728-isa-0a30",
+			Kind:    "voltage",
+			Chip:    "it8728-isa-0a30",
+			Voltage: &pb.VoltageDeviceMetrics{Label: "Vcore", Volts: 1.056, MinVolts: 0.9, MaxVolts: 1.5},
+		},
+	}
+	if diff := cmp.Diff(want, voltageDevices(chips), protocmp.Transform()); diff != "" {
+		t.Errorf("voltageDevices() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drivers
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Option describes an option that a driver accepts.
+type Option struct {
+	// Name of the option, like "address".
+	Name string
+	// Default is used when the option is not given. An option without a default is required if the driver asks for it with Required.
+	Default string
+	// Description is a one line summary of the option.
+	Description string
+}
+
+// Options holds the options of a driver, parsed from a driver spec like "simulated?profile=bursty&sockets=2", with the defaults of its registration filled in.
+// The first option that cannot be parsed is kept in Err so a driver can read all of its options before it checks for errors.
+type Options struct {
+	driver string
+	values map[string]string
+	err    error
+}
+
+// NewOptions creates the options of driver from values. The values are not checked against the options of the registration.
+func NewOptions(driver string, values map[string]string) *Options {
+	if values == nil {
+		values = map[string]string{}
+	}
+	return &Options{
+		driver: driver,
+		values: values,
+	}
+}
+
+// String returns the value of the option, or "" if it is not set.
+func (o *Options) String(name string) string {
+	return o.values[name]
+}
+
+// Required returns the value of the option and records an error if it is not set.
+func (o *Options) Required(name string) string {
+	value := o.values[name]
+	if value == "" {
+		o.fail(fmt.Errorf("cannot use driver '%s' without the '%s' option, like '%s?%s=...'", o.driver, name, o.driver, name))
+	}
+	return value
+}
+
+// Int returns the value of the option as an int, or 0 if it is not set.
+func (o *Options) Int(name string) int {
+	s := o.values[name]
+	if s == "" {
+		return 0
+	}
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		o.failParse(name, s, err)
+	}
+	return value
+}
+
+// Int64 returns the value of the option as an int64, or 0 if it is not set.
+func (o *Options) Int64(name string) int64 {
+	s := o.values[name]
+	if s == "" {
+		return 0
+	}
+	value, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		o.failParse(name, s, err)
+	}
+	return value
+}
+
+// Float returns the value of the option as a float64, or 0 if it is not set.
+func (o *Options) Float(name string) float64 {
+	s := o.values[name]
+	if s == "" {
+		return 0
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		o.failParse(name, s, err)
+	}
+	return value
+}
+
+// Bool returns the value of the option as a bool, or false if it is not set.
+func (o *Options) Bool(name string) bool {
+	s := o.values[name]
+	if s == "" {
+		return false
+	}
+	value, err := strconv.ParseBool(s)
+	if err != nil {
+		o.failParse(name, s, err)
+	}
+	return value
+}
+
+// Err returns the first option that could not be parsed or that is missing.
+func (o *Options) Err() error {
+	return o.err
+}
+
+func (o *Options) failParse(name string, value string, err error) {
+	o.fail(fmt.Errorf("cannot parse option '%s=%s' of driver '%s', err= %w", name, value, o.driver, err))
+}
+
+func (o *Options) fail(err error) {
+	if o.err == nil {
+		o.err = err
+	}
+}
+
+// parseSpec splits a driver spec, like "hwmon" or "coretempremote?address=gaming-pc:5200&name=gaming-pc", into the name of the driver and its options.
+// Options that reg does not accept are rejected and the defaults of reg are filled in.
+func (r *Registry) parseSpec(spec string) (*Registration, *Options, error) {
+	name, query, _ := strings.Cut(spec, "?")
+	reg, ok := r.Lookup(name)
+	if !ok {
+		return nil, nil, fmt.Errorf("cannot find driver '%s', the registered drivers are: %s", name, strings.Join(r.names(), ", "))
+	}
+	given, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse the options of driver '%s' in '%s', err= %w", name, spec, err)
+	}
+
+	values := map[string]string{}
+	for _, opt := range reg.Options {
+		if opt.Default != "" {
+			values[opt.Name] = opt.Default
+		}
+	}
+	for key, v := range given {
+		if !reg.hasOption(key) {
+			return nil, nil, fmt.Errorf("cannot use option '%s' of driver '%s', the options are: %s", key, name, reg.optionNames())
+		}
+		values[key] = v[len(v)-1]
+	}
+	return reg, NewOptions(name, values), nil
+}
+
+func (reg *Registration) hasOption(name string) bool {
+	for _, opt := range reg.Options {
+		if opt.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (reg *Registration) optionNames() string {
+	if len(reg.Options) == 0 {
+		return "none"
+	}
+	result := []string{}
+	for _, opt := range reg.Options {
+		result = append(result, opt.Name)
+	}
+	sort.Strings(result)
+	return strings.Join(result, ", ")
+}
@@ -0,0 +1,216 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package raspberrypi reads the SoC of a Raspberry Pi and the reasons its firmware throttles it.
+//
+// A Raspberry Pi does not have a coretemp adapter and its /proc/cpuinfo does not have a "model name", so the board model is read from the device tree and the SoC temperature from its thermal zone.
+// The throttled bitmask is read from the raspberrypi-firmware driver in sysfs, or from "vcgencmd get_throttled" on kernels that do not have it.
+package raspberrypi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	"github.com/jeremyje/coretemp-exporter/drivers/hwmon"
+	"github.com/jeremyje/coretemp-exporter/drivers/lmsensors"
+	"github.com/jeremyje/coretemp-exporter/drivers/thermal"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+const (
+	modelFile = "device-tree/model"
+	// throttledGlob matches the get_throttled node of the firmware driver, the device is "soc:firmware" on most boards.
+	throttledGlob = "bus/platform/drivers/raspberrypi-firmware/*/get_throttled"
+	// socZoneType is the type of the thermal zone of the SoC.
+	socZoneType = "cpu-thermal"
+	// socChip is the hwmon chip of the SoC zone, it is skipped because the SoC temperature is already the temperature of the CPU.
+	socChip = "cpu_thermal"
+)
+
+// Bits of the throttled bitmask, the low bits are the current state and the high bits are set once they happen until the next boot.
+const (
+	underVoltageBit                 = 1 << 0
+	frequencyCappedBit              = 1 << 1
+	throttledBit                    = 1 << 2
+	softTemperatureLimitBit         = 1 << 3
+	underVoltageOccurredBit         = 1 << 16
+	frequencyCappedOccurredBit      = 1 << 17
+	throttledOccurredBit            = 1 << 18
+	softTemperatureLimitOccurredBit = 1 << 19
+)
+
+// New creates a driver that reads the Raspberry Pi from /proc and /sys.
+func New() common.Driver {
+	return NewWithRoot(lmsensors.DefaultProcRoot, lmsensors.DefaultSysfsRoot)
+}
+
+// NewWithRoot creates a driver that reads the Raspberry Pi from procfs and sysfs trees mounted at procRoot and sysfsRoot.
+func NewWithRoot(procRoot string, sysfsRoot string) common.Driver {
+	return &raspberryPiDriver{
+		procRoot:  procRoot,
+		sysfsRoot: sysfsRoot,
+		command:   "vcgencmd",
+		host:      lmsensors.NewHostWithRoot(procRoot, sysfsRoot),
+		thermal:   thermal.NewWithRoot(sysfsRoot),
+	}
+}
+
+// Available returns true if this machine is a Raspberry Pi.
+func Available() bool {
+	return Probe() == nil
+}
+
+// Probe returns an error that explains why this machine is not a Raspberry Pi, or nil if it is.
+func Probe() error {
+	_, err := readModel(lmsensors.DefaultProcRoot)
+	return err
+}
+
+type raspberryPiDriver struct {
+	procRoot  string
+	sysfsRoot string
+	// command is vcgencmd, it is only run if the firmware driver does not have a get_throttled node.
+	command string
+	host    *lmsensors.Host
+	thermal common.Driver
+}
+
+func (d *raspberryPiDriver) Get() (*pb.MachineMetrics, error) {
+	return d.GetContext(context.Background())
+}
+
+func (d *raspberryPiDriver) GetContext(ctx context.Context) (*pb.MachineMetrics, error) {
+	model, err := readModel(d.procRoot)
+	if err != nil {
+		return nil, err
+	}
+	temp, err := d.socTemperature()
+	if err != nil {
+		return nil, err
+	}
+
+	mm := d.host.Metrics(d.boardChips())
+	for _, device := range mm.GetDevice() {
+		if device.GetCpu() == nil {
+			continue
+		}
+		device.Name = model
+		device.Temperature = temp
+		device.Cpu.PackageTemperature = temp
+		// Older kernels do not have the get_throttled node and vcgencmd is not always installed, the SoC is still reported without it.
+		if throttled, err := d.readThrottled(ctx); err == nil {
+			device.Throttled = throttled
+		}
+	}
+	return mm, nil
+}
+
+// boardChips returns the hwmon chips of the board, like the fan of the case or the under-voltage sensor, without the chip of the SoC zone.
+// A Pi without hwmon is still reported with its SoC.
+func (d *raspberryPiDriver) boardChips() []*lmsensors.Chip {
+	chips, err := hwmon.ReadChips(d.sysfsRoot)
+	if err != nil {
+		return nil
+	}
+	result := []*lmsensors.Chip{}
+	for _, chip := range chips {
+		if chip.Prefix() != socChip {
+			result = append(result, chip)
+		}
+	}
+	return result
+}
+
+// socTemperature returns the temperature of the cpu-thermal zone, or of the first zone if none is called that.
+func (d *raspberryPiDriver) socTemperature() (float64, error) {
+	zones, err := d.thermal.Get()
+	if err != nil {
+		return 0, err
+	}
+	var soc *pb.DeviceMetrics
+	for _, zone := range zones.GetDevice() {
+		if zone.GetThermalZone() == nil {
+			continue
+		}
+		if zone.GetThermalZone().GetType() == socZoneType {
+			return zone.GetTemperature(), nil
+		}
+		if soc == nil {
+			soc = zone
+		}
+	}
+	if soc == nil {
+		return 0, fmt.Errorf("cannot find the '%s' thermal zone in '%s'", socZoneType, filepath.Join(d.sysfsRoot, "class/thermal"))
+	}
+	return soc.GetTemperature(), nil
+}
+
+func (d *raspberryPiDriver) readThrottled(ctx context.Context) (*pb.ThrottledMetrics, error) {
+	matches, err := filepath.Glob(filepath.Join(d.sysfsRoot, throttledGlob))
+	if err == nil && len(matches) > 0 {
+		data, err := os.ReadFile(matches[0])
+		if err == nil {
+			return parseThrottled(string(data))
+		}
+	}
+
+	out, err := exec.CommandContext(ctx, d.command, "get_throttled").Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot run '%s get_throttled', err= %w", d.command, err)
+	}
+	return parseThrottled(string(out))
+}
+
+// parseThrottled decodes the bitmask of "vcgencmd get_throttled", like "throttled=0x50005", or of the get_throttled node, like "50005".
+func parseThrottled(text string) (*pb.ThrottledMetrics, error) {
+	value := strings.TrimSpace(text)
+	value = strings.TrimPrefix(value, "throttled=")
+	value = strings.TrimPrefix(value, "0x")
+	flags, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse throttled bitmask '%s', err= %w", strings.TrimSpace(text), err)
+	}
+	return &pb.ThrottledMetrics{
+		Flags:                        uint32(flags),
+		UnderVoltage:                 flags&underVoltageBit != 0,
+		FrequencyCapped:              flags&frequencyCappedBit != 0,
+		Throttled:                    flags&throttledBit != 0,
+		SoftTemperatureLimit:         flags&softTemperatureLimitBit != 0,
+		UnderVoltageOccurred:         flags&underVoltageOccurredBit != 0,
+		FrequencyCappedOccurred:      flags&frequencyCappedOccurredBit != 0,
+		ThrottledOccurred:            flags&throttledOccurredBit != 0,
+		SoftTemperatureLimitOccurred: flags&softTemperatureLimitOccurredBit != 0,
+	}, nil
+}
+
+// readModel returns the board model from the device tree, like "Raspberry Pi 4 Model B Rev 1.4".
+func readModel(procRoot string) (string, error) {
+	name := filepath.Join(procRoot, modelFile)
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("cannot read '%s', err= %w", name, err)
+	}
+	// Strings in the device tree end with a NUL.
+	model := strings.TrimSpace(strings.TrimRight(string(data), "\x00"))
+	if !strings.HasPrefix(model, "Raspberry Pi") {
+		return "", fmt.Errorf("cannot use '%s', it is not a Raspberry Pi", model)
+	}
+	return model, nil
+}
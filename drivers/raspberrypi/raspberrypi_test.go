@@ -0,0 +1,180 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raspberrypi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const (
+	testProcRoot  = "testdata/pi4/proc"
+	testSysfsRoot = "testdata/pi4/sys"
+)
+
+func ExampleNew() {
+	info, err := New().Get()
+	if err != nil {
+		fmt.Printf("ERROR: %s", err)
+	}
+	fmt.Printf("raspberrypi: %+v", info)
+}
+
+func TestGet(t *testing.T) {
+	got, err := NewWithRoot(testProcRoot, testSysfsRoot).Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*pb.DeviceMetrics{{
+		Name:        "Raspberry Pi 4 Model B Rev 1.4",
+		Kind:        "cpu",
+		Temperature: 48.686,
+		Cpu: &pb.CpuDeviceMetrics{
+			Load:               []int32{},
+			Temperature:        []float64{},
+			NumCores:           4,
+			FrequencyMhz:       1500,
+			CoreFrequencyHz:    []float64{1500000000, 1500000000, 1500000000, 1500000000},
+			CoreFrequencyMinHz: []float64{600000000, 600000000, 600000000, 600000000},
+			CoreFrequencyMaxHz: []float64{1800000000, 1800000000, 1800000000, 1800000000},
+			PackageTemperature: 48.686,
+		},
+		Throttled: &pb.ThrottledMetrics{
+			Flags:                0x50005,
+			UnderVoltage:         true,
+			Throttled:            true,
+			UnderVoltageOccurred: true,
+			ThrottledOccurred:    true,
+		},
+	}, {
+		// The fan of the case is read from hwmon, the cpu_thermal chip is the SoC temperature and is not reported again.
+		Name: "pwmfan-virtual-0",
+		Kind: "fan",
+		Chip: "pwmfan-virtual-0",
+		Fan:  &pb.FanDeviceMetrics{Label: "fan1", Rpm: 2950, PwmPercent: 40},
+	}}
+	if diff := cmp.Diff(want, got.GetDevice(), protocmp.Transform()); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseThrottled(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    *pb.ThrottledMetrics
+		wantErr bool
+	}{
+		{
+			input: "throttled=0x0\n",
+			want:  &pb.ThrottledMetrics{},
+		},
+		{
+			input: "throttled=0x80008\n",
+			want:  &pb.ThrottledMetrics{Flags: 0x80008, SoftTemperatureLimit: true, SoftTemperatureLimitOccurred: true},
+		},
+		{
+			input: "20002\n",
+			want:  &pb.ThrottledMetrics{Flags: 0x20002, FrequencyCapped: true, FrequencyCappedOccurred: true},
+		},
+		{
+			input: "e0000",
+			want:  &pb.ThrottledMetrics{Flags: 0xe0000, FrequencyCappedOccurred: true, ThrottledOccurred: true, SoftTemperatureLimitOccurred: true},
+		},
+		{
+			input:   "VCHI initialization failed",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseThrottled(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("parseThrottled() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadThrottledFromVcgencmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake vcgencmd is a shell script")
+	}
+	dir := t.TempDir()
+	command := filepath.Join(dir, "vcgencmd")
+	if err := os.WriteFile(command, []byte("#!/bin/sh\necho \"throttled=0x80008\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// The sysfs tree does not have a get_throttled node.
+	d := NewWithRoot(testProcRoot, dir).(*raspberryPiDriver)
+	d.command = command
+	got, err := d.readThrottled(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pb.ThrottledMetrics{Flags: 0x80008, SoftTemperatureLimit: true, SoftTemperatureLimitOccurred: true}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("readThrottled() mismatch (-want +got):\n%s", diff)
+	}
+
+	d.command = filepath.Join(dir, "missing")
+	if _, err := d.readThrottled(context.Background()); err == nil {
+		t.Error("expected an error without get_throttled and vcgencmd")
+	}
+}
+
+func TestReadModel(t *testing.T) {
+	got, err := readModel(testProcRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Raspberry Pi 4 Model B Rev 1.4" {
+		t.Errorf("expected the model without the NUL, got %q", got)
+	}
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "device-tree"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, modelFile), []byte("Pine64 RockPro64 v2.1\x00"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readModel(dir); err == nil || !strings.Contains(err.Error(), "not a Raspberry Pi") {
+		t.Errorf("expected 'not a Raspberry Pi', got %v", err)
+	}
+}
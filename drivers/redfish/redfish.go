@@ -0,0 +1,194 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redfish reads the temperatures, fans and power supplies of a BMC through its Redfish API.
+//
+// Every chassis in /redfish/v1/Chassis is read from its ThermalSubsystem, or from the deprecated Thermal resource if the BMC does not have one, and from its Power resource.
+package redfish
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// PasswordEnv is the environment variable the exporter reads the password of the BMCs from.
+	PasswordEnv = "REDFISH_PASSWORD"
+	chassisPath = "/redfish/v1/Chassis"
+)
+
+// Options controls how the BMC is read.
+type Options struct {
+	// User and Password are sent with basic authentication if User is set. A target with credentials has to use https so they are never sent in the clear.
+	User     string
+	Password string
+	// Name is the name of the machine in the metrics, it defaults to the host of the target.
+	Name string
+	// Insecure skips the verification of the certificate of the BMC, most BMCs have a self-signed certificate.
+	Insecure bool
+	// Client sends the requests, it defaults to http.DefaultClient or a client that skips the verification of certificates if Insecure is set.
+	Client *http.Client
+}
+
+// New creates a driver that reads the BMC at target, like "10.0.0.5" or "https://bmc.example.com:8443". A target without a scheme uses https.
+func New(target string, opts Options) common.Driver {
+	if !strings.Contains(target, "://") {
+		target = "https://" + target
+	}
+	target = strings.TrimSuffix(target, "/")
+	if opts.Name == "" {
+		opts.Name = target
+		if u, err := url.Parse(target); err == nil {
+			opts.Name = u.Hostname()
+		}
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+		if opts.Insecure {
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+			opts.Client = &http.Client{Transport: transport}
+		}
+	}
+	return &redfishDriver{
+		target: target,
+		opts:   opts,
+	}
+}
+
+type redfishDriver struct {
+	target string
+	opts   Options
+}
+
+func (d *redfishDriver) Get() (*pb.MachineMetrics, error) {
+	return d.GetContext(context.Background())
+}
+
+func (d *redfishDriver) GetContext(ctx context.Context) (*pb.MachineMetrics, error) {
+	if (d.opts.User != "" || d.opts.Password != "") && !strings.HasPrefix(strings.ToLower(d.target), "https://") {
+		return nil, fmt.Errorf("cannot send the credentials of the BMC to '%s' without https", d.target)
+	}
+	chassis := &collection{}
+	if err := d.get(ctx, chassisPath, chassis); err != nil {
+		return nil, err
+	}
+
+	devices := []*pb.DeviceMetrics{}
+	for _, member := range chassis.Members {
+		result, err := d.readChassis(ctx, member.ID)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, result...)
+	}
+	return &pb.MachineMetrics{
+		Name:      d.opts.Name,
+		Timestamp: timestamppb.Now(),
+		Device:    devices,
+	}, nil
+}
+
+// readChassis reads the thermal and power resources of a chassis.
+func (d *redfishDriver) readChassis(ctx context.Context, path string) ([]*pb.DeviceMetrics, error) {
+	c := &chassis{}
+	if err := d.get(ctx, path, c); err != nil {
+		return nil, err
+	}
+	m := newChassisMapper(c)
+
+	switch {
+	case c.ThermalSubsystem != nil:
+		subsystem := &thermalSubsystem{}
+		if err := d.get(ctx, c.ThermalSubsystem.ID, subsystem); err != nil {
+			return nil, err
+		}
+		if subsystem.ThermalMetrics != nil {
+			metrics := &thermalMetrics{}
+			if err := d.get(ctx, subsystem.ThermalMetrics.ID, metrics); err != nil {
+				return nil, err
+			}
+			m.addThermalMetrics(metrics)
+		}
+		if subsystem.Fans != nil {
+			fans := &collection{}
+			if err := d.get(ctx, subsystem.Fans.ID, fans); err != nil {
+				return nil, err
+			}
+			for _, member := range fans.Members {
+				f := &fan{}
+				if err := d.get(ctx, member.ID, f); err != nil {
+					return nil, err
+				}
+				m.addFan(f)
+			}
+		}
+	case c.Thermal != nil:
+		t := &thermal{}
+		if err := d.get(ctx, c.Thermal.ID, t); err != nil {
+			return nil, err
+		}
+		m.addThermal(t)
+	}
+
+	if c.Power != nil {
+		p := &power{}
+		if err := d.get(ctx, c.Power.ID, p); err != nil {
+			return nil, err
+		}
+		m.addPower(p)
+	}
+	return m.devices(), nil
+}
+
+// get reads the resource at path, like "/redfish/v1/Chassis", into v.
+func (d *redfishDriver) get(ctx context.Context, path string, v interface{}) error {
+	u := d.target + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("cannot create request for '%s', err= %w", u, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("OData-Version", "4.0")
+	if d.opts.User != "" {
+		req.SetBasicAuth(d.opts.User, d.opts.Password)
+	}
+
+	resp, err := d.opts.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot read Redfish resource '%s', err= %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cannot read Redfish resource '%s', status= %s", u, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read Redfish resource '%s', err= %w", u, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("cannot parse Redfish resource '%s', err= %w", u, err)
+	}
+	return nil
+}
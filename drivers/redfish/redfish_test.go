@@ -0,0 +1,173 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// mockHandler serves the resources in testdata/mockup, laid out like the DMTF mockups with an index.json per resource.
+func mockHandler(t *testing.T) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "root" || password != "calvin" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", "mockup", filepath.FromSlash(r.URL.Path), "index.json"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+func mockupDevices() []*pb.DeviceMetrics {
+	const (
+		dell = "Computer System Chassis"
+		chip = "redfish-System.Embedded.1"
+	)
+	return []*pb.DeviceMetrics{
+		{
+			Name: dell,
+			Kind: "sensor",
+			Chip: chip,
+			Sensor: []*pb.SensorReading{
+				{Label: "CPU1 Temp", Type: "temp", Unit: "C", Value: 45, Crit: 95},
+				{Label: "System Board Inlet Temp", Type: "temp", Unit: "C", Value: 23, Max: 42, Crit: 47},
+				{Label: "System Board Exhaust Temp", Type: "temp", Unit: "C", Value: 71, Max: 70, Crit: 75, Alarm: true},
+				{Label: "System Power Control", Type: "power", Unit: "W", Value: 168},
+			},
+		},
+		{Name: dell, Kind: "fan", Chip: chip, Fan: &pb.FanDeviceMetrics{Label: "System Board Fan1A", Rpm: 5880}},
+		{Name: dell, Kind: "fan", Chip: chip, Fan: &pb.FanDeviceMetrics{Label: "System Board Fan2A", Rpm: 5760}},
+		{Name: dell, Kind: "fan", Chip: chip, Fan: &pb.FanDeviceMetrics{Label: "Blade Fan", PwmPercent: 40, Alarm: true}},
+		{Name: dell, Kind: "voltage", Chip: chip, Voltage: &pb.VoltageDeviceMetrics{Label: "CPU1 VCORE", Volts: 1.79}},
+		{
+			Name: "PS1 Status",
+			Kind: "psu",
+			Chip: chip,
+			Sensor: []*pb.SensorReading{
+				{Label: "Input Power", Type: "power", Unit: "W", Value: 190},
+				{Label: "Output Power", Type: "power", Unit: "W", Value: 168},
+				{Label: "Input Voltage", Type: "in", Unit: "V", Value: 230},
+			},
+		},
+		{
+			Name: "PS2 Status",
+			Kind: "psu",
+			Chip: chip,
+			Sensor: []*pb.SensorReading{
+				{Label: "Input Power", Type: "power", Unit: "W", Value: 12, Alarm: true},
+				{Label: "Output Power", Type: "power", Unit: "W", Value: 0, Alarm: true},
+			},
+		},
+		{
+			Name: "1U Server",
+			Kind: "sensor",
+			Chip: "redfish-1U",
+			Sensor: []*pb.SensorReading{
+				{Label: "CPU1", Type: "temp", Unit: "C", Value: 44.5},
+				{Label: "IntakeTemp", Type: "temp", Unit: "C", Value: 24},
+			},
+		},
+		{Name: "1U Server", Kind: "fan", Chip: "redfish-1U", Fan: &pb.FanDeviceMetrics{Label: "Fan 1", Rpm: 3150, PwmPercent: 45}},
+	}
+}
+
+func TestGet(t *testing.T) {
+	srv := httptest.NewTLSServer(mockHandler(t))
+	t.Cleanup(srv.Close)
+
+	got, err := New(srv.URL, Options{User: "root", Password: "calvin", Client: srv.Client()}).Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetName() != "127.0.0.1" {
+		t.Errorf("expected the host of the target as name, got %q", got.GetName())
+	}
+	if got.GetTimestamp() == nil {
+		t.Error("expected a timestamp")
+	}
+	if diff := cmp.Diff(mockupDevices(), got.GetDevice(), protocmp.Transform()); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(mockHandler(t))
+	t.Cleanup(srv.Close)
+
+	if _, err := New(srv.URL, Options{User: "root", Password: "calvin"}).Get(); err == nil {
+		t.Error("expected an error for a self-signed certificate")
+	}
+	got, err := New(srv.URL, Options{User: "root", Password: "calvin", Insecure: true, Name: "rack1-node3"}).Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetName() != "rack1-node3" {
+		t.Errorf("expected name %q, got %q", "rack1-node3", got.GetName())
+	}
+}
+
+func TestGetError(t *testing.T) {
+	srv := httptest.NewTLSServer(mockHandler(t))
+	t.Cleanup(srv.Close)
+
+	if _, err := New(srv.URL, Options{User: "root", Password: "wrong", Client: srv.Client()}).Get(); err == nil {
+		t.Error("expected an error for a wrong password")
+	}
+	if _, err := New(srv.URL+"/missing", Options{User: "root", Password: "calvin", Client: srv.Client()}).Get(); err == nil {
+		t.Error("expected an error for a server without Redfish")
+	}
+}
+
+func TestGetCredentialsWithoutTLS(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	for _, opts := range []Options{{User: "root", Password: "calvin"}, {Password: "calvin"}} {
+		if _, err := New(srv.URL, opts).Get(); err == nil || !strings.Contains(err.Error(), "without https") {
+			t.Errorf("Get() error = %v, want a refusal to send the credentials over http", err)
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Errorf("the credentials were sent over http in %d requests", got)
+	}
+
+	// Without credentials there is nothing to leak.
+	if _, err := New(srv.URL, Options{}).Get(); err == nil {
+		t.Error("expected an error for a server without Redfish")
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("got %d requests, want 1", got)
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"path"
+
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+// The resources only have the properties that are mapped, readings are pointers because BMCs report missing readings as null.
+
+type link struct {
+	ID string `json:"@odata.id"`
+}
+
+type collection struct {
+	Members []link `json:"Members"`
+}
+
+type status struct {
+	State  string `json:"State"`
+	Health string `json:"Health"`
+}
+
+func (s status) absent() bool {
+	return s.State == "Absent"
+}
+
+func (s status) alarm() bool {
+	return s.Health == "Warning" || s.Health == "Critical"
+}
+
+type chassis struct {
+	ID               string `json:"Id"`
+	Name             string `json:"Name"`
+	Thermal          *link  `json:"Thermal"`
+	ThermalSubsystem *link  `json:"ThermalSubsystem"`
+	Power            *link  `json:"Power"`
+}
+
+type thermal struct {
+	Temperatures []temperature `json:"Temperatures"`
+	Fans         []legacyFan   `json:"Fans"`
+}
+
+type temperature struct {
+	Name                      string   `json:"Name"`
+	ReadingCelsius            *float64 `json:"ReadingCelsius"`
+	UpperThresholdNonCritical *float64 `json:"UpperThresholdNonCritical"`
+	UpperThresholdCritical    *float64 `json:"UpperThresholdCritical"`
+	Status                    status   `json:"Status"`
+}
+
+// legacyFan is a fan of the Thermal resource, older BMCs name it with FanName.
+type legacyFan struct {
+	Name         string   `json:"Name"`
+	FanName      string   `json:"FanName"`
+	Reading      *float64 `json:"Reading"`
+	ReadingUnits string   `json:"ReadingUnits"`
+	Status       status   `json:"Status"`
+}
+
+type thermalSubsystem struct {
+	ThermalMetrics *link `json:"ThermalMetrics"`
+	Fans           *link `json:"Fans"`
+}
+
+type thermalMetrics struct {
+	TemperatureReadingsCelsius []sensorExcerpt `json:"TemperatureReadingsCelsius"`
+}
+
+type sensorExcerpt struct {
+	DataSourceURI string   `json:"DataSourceUri"`
+	DeviceName    string   `json:"DeviceName"`
+	Reading       *float64 `json:"Reading"`
+}
+
+// fan is a fan of the ThermalSubsystem.
+type fan struct {
+	Name         string `json:"Name"`
+	SpeedPercent *struct {
+		Reading  *float64 `json:"Reading"`
+		SpeedRPM *float64 `json:"SpeedRPM"`
+	} `json:"SpeedPercent"`
+	Status status `json:"Status"`
+}
+
+type power struct {
+	PowerControl  []powerControl `json:"PowerControl"`
+	PowerSupplies []powerSupply  `json:"PowerSupplies"`
+	Voltages      []voltage      `json:"Voltages"`
+}
+
+type powerControl struct {
+	Name               string   `json:"Name"`
+	PowerConsumedWatts *float64 `json:"PowerConsumedWatts"`
+}
+
+type powerSupply struct {
+	Name                 string   `json:"Name"`
+	PowerInputWatts      *float64 `json:"PowerInputWatts"`
+	PowerOutputWatts     *float64 `json:"PowerOutputWatts"`
+	LastPowerOutputWatts *float64 `json:"LastPowerOutputWatts"`
+	LineInputVoltage     *float64 `json:"LineInputVoltage"`
+	Status               status   `json:"Status"`
+}
+
+type voltage struct {
+	Name         string   `json:"Name"`
+	ReadingVolts *float64 `json:"ReadingVolts"`
+	Status       status   `json:"Status"`
+}
+
+// chassisMapper collects the devices of a chassis. The temperatures and the power consumption are readings of one device, like the sensors of a hwmon chip, fans, voltages and power supplies are devices of their own.
+type chassisMapper struct {
+	sensor   *pb.DeviceMetrics
+	fans     []*pb.DeviceMetrics
+	voltages []*pb.DeviceMetrics
+	psus     []*pb.DeviceMetrics
+}
+
+func newChassisMapper(c *chassis) *chassisMapper {
+	return &chassisMapper{
+		sensor: &pb.DeviceMetrics{
+			Name: c.Name,
+			Kind: "sensor",
+			Chip: "redfish-" + c.ID,
+		},
+	}
+}
+
+func (m *chassisMapper) devices() []*pb.DeviceMetrics {
+	result := []*pb.DeviceMetrics{}
+	if len(m.sensor.Sensor) > 0 {
+		result = append(result, m.sensor)
+	}
+	result = append(result, m.fans...)
+	result = append(result, m.voltages...)
+	return append(result, m.psus...)
+}
+
+func (m *chassisMapper) addThermal(t *thermal) {
+	for _, temp := range t.Temperatures {
+		if temp.Status.absent() || temp.ReadingCelsius == nil {
+			continue
+		}
+		m.sensor.Sensor = append(m.sensor.Sensor, &pb.SensorReading{
+			Label: temp.Name,
+			Type:  "temp",
+			Unit:  "C",
+			Value: *temp.ReadingCelsius,
+			Max:   value(temp.UpperThresholdNonCritical),
+			Crit:  value(temp.UpperThresholdCritical),
+			Alarm: temp.Status.alarm(),
+		})
+	}
+	for _, f := range t.Fans {
+		if f.Status.absent() || f.Reading == nil {
+			continue
+		}
+		label := f.Name
+		if label == "" {
+			label = f.FanName
+		}
+		metrics := &pb.FanDeviceMetrics{Label: label, Alarm: f.Status.alarm()}
+		if f.ReadingUnits == "Percent" {
+			metrics.PwmPercent = *f.Reading
+		} else {
+			metrics.Rpm = *f.Reading
+		}
+		m.addFanDevice(metrics)
+	}
+}
+
+// addThermalMetrics adds the temperatures of the ThermalSubsystem, they are labeled with the device name or the name of the sensor resource.
+func (m *chassisMapper) addThermalMetrics(t *thermalMetrics) {
+	for _, temp := range t.TemperatureReadingsCelsius {
+		if temp.Reading == nil {
+			continue
+		}
+		label := temp.DeviceName
+		if label == "" {
+			label = path.Base(temp.DataSourceURI)
+		}
+		m.sensor.Sensor = append(m.sensor.Sensor, &pb.SensorReading{
+			Label: label,
+			Type:  "temp",
+			Unit:  "C",
+			Value: *temp.Reading,
+		})
+	}
+}
+
+func (m *chassisMapper) addFan(f *fan) {
+	if f.Status.absent() || f.SpeedPercent == nil {
+		return
+	}
+	m.addFanDevice(&pb.FanDeviceMetrics{
+		Label:      f.Name,
+		Rpm:        value(f.SpeedPercent.SpeedRPM),
+		PwmPercent: value(f.SpeedPercent.Reading),
+		Alarm:      f.Status.alarm(),
+	})
+}
+
+func (m *chassisMapper) addFanDevice(metrics *pb.FanDeviceMetrics) {
+	m.fans = append(m.fans, &pb.DeviceMetrics{
+		Name: m.sensor.Name,
+		Kind: "fan",
+		Chip: m.sensor.Chip,
+		Fan:  metrics,
+	})
+}
+
+func (m *chassisMapper) addPower(p *power) {
+	for _, control := range p.PowerControl {
+		if control.PowerConsumedWatts == nil {
+			continue
+		}
+		m.sensor.Sensor = append(m.sensor.Sensor, &pb.SensorReading{
+			Label: control.Name,
+			Type:  "power",
+			Unit:  "W",
+			Value: *control.PowerConsumedWatts,
+		})
+	}
+
+	for _, v := range p.Voltages {
+		if v.Status.absent() || v.ReadingVolts == nil {
+			continue
+		}
+		m.voltages = append(m.voltages, &pb.DeviceMetrics{
+			Name:    m.sensor.Name,
+			Kind:    "voltage",
+			Chip:    m.sensor.Chip,
+			Voltage: &pb.VoltageDeviceMetrics{Label: v.Name, Volts: *v.ReadingVolts, Alarm: v.Status.alarm()},
+		})
+	}
+
+	for _, psu := range p.PowerSupplies {
+		if psu.Status.absent() {
+			continue
+		}
+		output := psu.PowerOutputWatts
+		if output == nil {
+			output = psu.LastPowerOutputWatts
+		}
+		d := &pb.DeviceMetrics{
+			Name: psu.Name,
+			Kind: "psu",
+			Chip: m.sensor.Chip,
+		}
+		for _, r := range []struct {
+			label      string
+			sensorType string
+			unit       string
+			value      *float64
+		}{
+			{label: "Input Power", sensorType: "power", unit: "W", value: psu.PowerInputWatts},
+			{label: "Output Power", sensorType: "power", unit: "W", value: output},
+			{label: "Input Voltage", sensorType: "in", unit: "V", value: psu.LineInputVoltage},
+		} {
+			if r.value == nil {
+				continue
+			}
+			d.Sensor = append(d.Sensor, &pb.SensorReading{
+				Label: r.label,
+				Type:  r.sensorType,
+				Unit:  r.unit,
+				Value: *r.value,
+				Alarm: psu.Status.alarm(),
+			})
+		}
+		if len(d.Sensor) > 0 {
+			m.psus = append(m.psus, d)
+		}
+	}
+}
+
+// value returns 0 for a missing reading or limit, like the other drivers do for limits that are not set.
+func value(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
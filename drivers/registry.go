@@ -0,0 +1,433 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drivers
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	"github.com/jeremyje/coretemp-exporter/drivers/composite"
+	"github.com/jeremyje/coretemp-exporter/drivers/coretempremote"
+	"github.com/jeremyje/coretemp-exporter/drivers/coretempsdk"
+	"github.com/jeremyje/coretemp-exporter/drivers/hwmon"
+	"github.com/jeremyje/coretemp-exporter/drivers/ipmi"
+	"github.com/jeremyje/coretemp-exporter/drivers/lhm"
+	"github.com/jeremyje/coretemp-exporter/drivers/lmsensors"
+	"github.com/jeremyje/coretemp-exporter/drivers/raspberrypi"
+	"github.com/jeremyje/coretemp-exporter/drivers/redfish"
+	"github.com/jeremyje/coretemp-exporter/drivers/replay"
+	"github.com/jeremyje/coretemp-exporter/drivers/simulated"
+	"github.com/jeremyje/coretemp-exporter/drivers/thermal"
+)
+
+// Registration describes a driver that can be selected by name.
+type Registration struct {
+	// Name selects the driver, like "hwmon".
+	Name string
+	// Description is a one line summary of where the driver reads from.
+	Description string
+	// Options are the options the driver accepts, any other option is rejected.
+	Options []Option
+	// New creates the driver with its options.
+	New func(opts *Options) (common.Driver, error)
+	// Probe returns an error that explains why the driver cannot run on this machine, or nil if it can.
+	Probe func() error
+	// Standalone drivers, like a replay, report their own machine instead of being merged with the other drivers of this machine.
+	Standalone bool
+	// Conflicts are the drivers that report the same sensors as different devices, they cannot be selected together because every reading would be exported twice.
+	// Drivers that report the same devices, like hwmon and lmsensors, do not conflict, the composite driver keeps each device once.
+	Conflicts []string
+}
+
+// Registry holds the drivers that can be selected by name.
+type Registry struct {
+	mu            sync.RWMutex
+	registrations map[string]*Registration
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		registrations: map[string]*Registration{},
+	}
+}
+
+// Register adds a driver to the registry. It returns an error if the name is empty or already taken.
+func (r *Registry) Register(reg *Registration) error {
+	if reg.Name == "" {
+		return fmt.Errorf("cannot register a driver without a name")
+	}
+	if reg.New == nil || reg.Probe == nil {
+		return fmt.Errorf("cannot register driver '%s' without a constructor and a probe", reg.Name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.registrations[reg.Name]; ok {
+		return fmt.Errorf("cannot register driver '%s', it is already registered", reg.Name)
+	}
+	r.registrations[reg.Name] = reg
+	return nil
+}
+
+// Registrations returns every registered driver sorted by name.
+func (r *Registry) Registrations() []*Registration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := []*Registration{}
+	for _, reg := range r.registrations {
+		result = append(result, reg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Lookup returns the driver registered under name.
+func (r *Registry) Lookup(name string) (*Registration, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	reg, ok := r.registrations[name]
+	return reg, ok
+}
+
+// New creates the drivers of specs, like "hwmon" or "replay?file=cputemps.ndjson&loop=true".
+// The drivers of this machine are merged with the composite driver so their devices are reported together, they are followed by one driver for each standalone driver.
+// It returns an error if a driver is not registered, if an option is not valid or if a driver cannot run on this machine.
+func (r *Registry) New(specs ...string) ([]common.Driver, error) {
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("cannot create a driver, no driver names were given")
+	}
+	regs := make([]*Registration, len(specs))
+	opts := make([]*Options, len(specs))
+	selected := map[string]bool{}
+	for i, spec := range specs {
+		reg, o, err := r.parseSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		regs[i] = reg
+		opts[i] = o
+		selected[reg.Name] = true
+	}
+
+	local := []common.Driver{}
+	standalone := []common.Driver{}
+	for i, reg := range regs {
+		for _, conflict := range reg.Conflicts {
+			if selected[conflict] {
+				return nil, fmt.Errorf("cannot use driver '%s' together with '%s', they read the same sensors", reg.Name, conflict)
+			}
+		}
+		if err := reg.Probe(); err != nil {
+			return nil, fmt.Errorf("cannot use driver '%s' on this machine, err= %w", reg.Name, err)
+		}
+		d, err := reg.New(opts[i])
+		if err == nil {
+			err = opts[i].Err()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if reg.Standalone {
+			standalone = append(standalone, d)
+		} else {
+			local = append(local, d)
+		}
+	}
+
+	switch len(local) {
+	case 0:
+		return standalone, nil
+	case 1:
+		return append(local, standalone...), nil
+	}
+	return append([]common.Driver{composite.New(local...)}, standalone...), nil
+}
+
+// NewTarget creates the standalone driver of spec, like "redfish?user=root", that reads target through its address option.
+// It is used by /probe to read the targets of a module.
+func (r *Registry) NewTarget(spec string, target string) (common.Driver, error) {
+	reg, opts, err := r.parseTargetSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	opts.values[targetOption] = target
+	if err := reg.Probe(); err != nil {
+		return nil, fmt.Errorf("cannot use driver '%s' on this machine, err= %w", reg.Name, err)
+	}
+	d, err := reg.New(opts)
+	if err == nil {
+		err = opts.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// CheckTarget returns an error if spec is not a driver that NewTarget can create.
+func (r *Registry) CheckTarget(spec string) error {
+	_, _, err := r.parseTargetSpec(spec)
+	return err
+}
+
+func (r *Registry) parseTargetSpec(spec string) (*Registration, *Options, error) {
+	reg, opts, err := r.parseSpec(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !reg.Standalone || !reg.hasOption(targetOption) {
+		return nil, nil, fmt.Errorf("cannot probe with driver '%s', it does not read a remote machine", reg.Name)
+	}
+	if opts.String(targetOption) != "" {
+		return nil, nil, fmt.Errorf("cannot probe with driver '%s' and the '%s' option, the target is the address", reg.Name, targetOption)
+	}
+	return reg, opts, nil
+}
+
+// withoutOptions adapts the constructor of a driver that does not have any options.
+func withoutOptions(newDriver func() common.Driver) func(opts *Options) (common.Driver, error) {
+	return func(opts *Options) (common.Driver, error) {
+		return newDriver(), nil
+	}
+}
+
+func (r *Registry) names() []string {
+	result := []string{}
+	for _, reg := range r.Registrations() {
+		result = append(result, reg.Name)
+	}
+	return result
+}
+
+// targetOption is the option of the remote drivers that holds the address of the remote machine.
+const targetOption = "address"
+
+var defaultRegistry = newDefaultRegistry()
+
+// newDefaultRegistry registers every driver on every platform so the drivers that cannot run here can explain why.
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, reg := range []*Registration{
+		{
+			Name:        "coretempremote",
+			Description: "Core Temp of another machine through its Core Temp Remote Server plugin",
+			Options: []Option{
+				{Name: "address", Description: "Address of the Remote Server, like 'gaming-pc' or 'gaming-pc:5201', the port defaults to " + coretempremote.DefaultPort},
+				{Name: "name", Description: "Name of the machine, it defaults to the host of the address"},
+			},
+			New: func(opts *Options) (common.Driver, error) {
+				return coretempremote.New(opts.Required("address"), coretempremote.Options{
+					Name: opts.String("name"),
+				}), nil
+			},
+			Probe:      func() error { return nil },
+			Standalone: true,
+		},
+		{
+			Name:        "coretempsdk",
+			Description: "Core Temp on Windows through GetCoreTempInfo.dll",
+			New:         withoutOptions(coretempsdk.New),
+			Probe:       coretempsdk.Probe,
+		},
+		{
+			Name:        "hwmon",
+			Description: "Linux hwmon sensors read directly from /sys/class/hwmon",
+			New:         withoutOptions(hwmon.New),
+			Probe:       hwmon.Probe,
+			Conflicts:   []string{"raspberrypi"},
+		},
+		{
+			Name:        "ipmi",
+			Description: "BMC sensors from 'ipmitool sdr elist full'",
+			New: withoutOptions(func() common.Driver {
+				return ipmi.New(ipmi.Options{})
+			}),
+			Probe: ipmi.Probe,
+		},
+		{
+			Name:        "ipmiremote",
+			Description: "Sensors of a remote BMC from 'ipmitool sdr elist full', the password is read from the IPMI_PASSWORD environment variable",
+			Options: []Option{
+				{Name: "address", Description: "Address of the BMC, like '10.0.0.5'"},
+				{Name: "user", Description: "User of the BMC"},
+				{Name: "interface", Default: ipmi.DefaultInterface, Description: "ipmitool interface used to reach the BMC"},
+				{Name: "name", Description: "Name of the machine, it defaults to the address"},
+			},
+			New: func(opts *Options) (common.Driver, error) {
+				return ipmi.New(ipmi.Options{
+					Host:      opts.Required("address"),
+					User:      opts.String("user"),
+					Interface: opts.String("interface"),
+					Name:      opts.String("name"),
+				}), nil
+			},
+			Probe:      ipmi.Probe,
+			Standalone: true,
+		},
+		{
+			Name:        "lhm",
+			Description: "Sensor tree of another machine through the web server of LibreHardwareMonitor or OpenHardwareMonitor",
+			Options: []Option{
+				{Name: "address", Description: "Address of the web server, like 'gaming-pc', 'gaming-pc:9000' or 'http://gaming-pc:8085/data.json', the port defaults to " + lhm.DefaultPort},
+				{Name: "name", Description: "Name of the machine, it defaults to the computer name in the sensor tree"},
+			},
+			New: func(opts *Options) (common.Driver, error) {
+				return lhm.New(opts.Required("address"), lhm.Options{
+					Name: opts.String("name"),
+				}), nil
+			},
+			Probe:      func() error { return nil },
+			Standalone: true,
+		},
+		{
+			Name:        "lmsensors",
+			Description: "Output of 'sensors -j' from lm-sensors",
+			New:         withoutOptions(lmsensors.New),
+			Probe:       lmsensors.Probe,
+			Conflicts:   []string{"raspberrypi"},
+		},
+		{
+			Name:        "redfish",
+			Description: "BMC sensors of a server through its Redfish API, the password is read from the " + redfish.PasswordEnv + " environment variable",
+			Options: []Option{
+				{Name: "address", Description: "Address of the BMC, like '10.0.0.5' or 'https://bmc.example.com:8443', it uses https without a scheme"},
+				{Name: "user", Description: "User of the BMC, the BMC is read without authentication if it is not set"},
+				{Name: "insecure", Default: "false", Description: "Do not verify the certificate of the BMC, most BMCs have a self-signed certificate"},
+				{Name: "name", Description: "Name of the machine, it defaults to the host of the address"},
+			},
+			New: func(opts *Options) (common.Driver, error) {
+				return redfish.New(opts.Required("address"), redfish.Options{
+					User:     opts.String("user"),
+					Password: os.Getenv(redfish.PasswordEnv),
+					Insecure: opts.Bool("insecure"),
+					Name:     opts.String("name"),
+				}), nil
+			},
+			Probe:      func() error { return nil },
+			Standalone: true,
+		},
+		{
+			Name:        "replay",
+			Description: "Plays back an ndjson log written with -log instead of reading the sensors",
+			Options: []Option{
+				{Name: "file", Description: "ndjson log to play back"},
+				{Name: "realtime", Default: "false", Description: "Play back the records as far apart as they were recorded instead of one record per poll"},
+				{Name: "speed", Default: "1", Description: "How many times faster than recorded to play back in realtime mode"},
+				{Name: "loop", Default: "false", Description: "Start over after the last record"},
+				{Name: "now", Default: "false", Description: "Replace the timestamps of the records with the current time"},
+				{Name: "name", Description: "Replace the name of the machine of every record, it defaults to the recorded names"},
+			},
+			New: func(opts *Options) (common.Driver, error) {
+				return replay.New(opts.Required("file"), replay.Options{
+					Realtime: opts.Bool("realtime"),
+					Speed:    opts.Float("speed"),
+					Loop:     opts.Bool("loop"),
+					Now:      opts.Bool("now"),
+					Name:     opts.String("name"),
+				}), nil
+			},
+			Probe:      func() error { return nil },
+			Standalone: true,
+		},
+		{
+			Name:        "raspberrypi",
+			Description: "Raspberry Pi SoC temperature and firmware throttling from /sys and vcgencmd, with the other hwmon sensors of the board",
+			New:         withoutOptions(raspberrypi.New),
+			Probe:       raspberrypi.Probe,
+			Conflicts:   []string{"hwmon", "lmsensors"},
+		},
+		{
+			Name:        "simulated",
+			Description: "Makes up believable readings for a machine of any size instead of reading the sensors",
+			Options: []Option{
+				{Name: "profile", Default: string(simulated.Idle), Description: "Load of the CPUs: idle, bursty, sustained or throttling"},
+				{Name: "sockets", Default: "1", Description: "Number of CPUs"},
+				{Name: "cores", Default: "4", Description: "Number of cores of each CPU"},
+				{Name: "fans", Default: "2", Description: "Number of fans"},
+				{Name: "ambient", Default: "25", Description: "Ambient temperature in °C"},
+				{Name: "noise", Default: "0.5", Description: "Standard deviation of the noise of the temperatures in °C"},
+				{Name: "seed", Default: "1", Description: "Seed of the simulation, the same seed always produces the same readings"},
+				{Name: "name", Default: simulated.DefaultName, Description: "Name of the machine"},
+			},
+			New: func(opts *Options) (common.Driver, error) {
+				ambient := opts.Float("ambient")
+				cfg := simulated.Config{
+					Name:    opts.String("name"),
+					Sockets: opts.Int("sockets"),
+					Cores:   opts.Int("cores"),
+					Fans:    opts.Int("fans"),
+					Profile: simulated.Profile(opts.String("profile")),
+					Ambient: &ambient,
+					Noise:   opts.Float("noise"),
+					Seed:    opts.Int64("seed"),
+				}
+				if err := cfg.Validate(); err != nil {
+					return nil, err
+				}
+				// The config replaces 0 with a default, an explicit 0 is rejected instead of silently changed.
+				if cfg.Sockets == 0 || cfg.Cores == 0 {
+					return nil, fmt.Errorf("cannot simulate %d sockets with %d cores, a machine needs at least one of each", cfg.Sockets, cfg.Cores)
+				}
+				return simulated.New(cfg), nil
+			},
+			Probe:      func() error { return nil },
+			Standalone: true,
+		},
+		{
+			Name:        "thermal",
+			Description: "Linux thermal zones and cooling devices read directly from /sys/class/thermal",
+			New:         withoutOptions(thermal.New),
+			Probe:       thermal.Probe,
+		},
+	} {
+		if err := r.Register(reg); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register adds a driver to the default registry.
+func Register(reg *Registration) error {
+	return defaultRegistry.Register(reg)
+}
+
+// Registrations returns every driver in the default registry sorted by name.
+func Registrations() []*Registration {
+	return defaultRegistry.Registrations()
+}
+
+// NewTarget creates the driver of spec from the default registry that reads target.
+func NewTarget(spec string, target string) (common.Driver, error) {
+	return defaultRegistry.NewTarget(spec, target)
+}
+
+// CheckTarget returns an error if spec is not a driver of the default registry that NewTarget can create.
+func CheckTarget(spec string) error {
+	return defaultRegistry.CheckTarget(spec)
+}
+
+// NewByName creates the drivers of specs from the default registry. Without any specs it returns the default driver for this platform.
+func NewByName(specs ...string) ([]common.Driver, error) {
+	if len(specs) == 0 {
+		return []common.Driver{New()}, nil
+	}
+	return defaultRegistry.New(specs...)
+}
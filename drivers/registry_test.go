@@ -0,0 +1,293 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drivers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+type fakeDriver struct {
+	name string
+}
+
+func (d *fakeDriver) Get() (*pb.MachineMetrics, error) {
+	return &pb.MachineMetrics{
+		Device: []*pb.DeviceMetrics{{Name: d.name}},
+	}, nil
+}
+
+func fakeRegistration(name string, probeErr error) *Registration {
+	return &Registration{
+		Name: name,
+		New: withoutOptions(func() common.Driver {
+			return &fakeDriver{name: name}
+		}),
+		Probe: func() error {
+			return probeErr
+		},
+	}
+}
+
+func newFakeRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r := NewRegistry()
+	for _, reg := range []*Registration{
+		fakeRegistration("b", nil),
+		fakeRegistration("a", nil),
+		fakeRegistration("missing", errors.New("not installed")),
+		{
+			Name:      "c",
+			New:       withoutOptions(func() common.Driver { return &fakeDriver{name: "c"} }),
+			Probe:     func() error { return nil },
+			Conflicts: []string{"a"},
+		},
+		{
+			Name: "remote",
+			Options: []Option{
+				{Name: "address"},
+				{Name: "slow", Default: "false"},
+			},
+			New: func(opts *Options) (common.Driver, error) {
+				opts.Bool("slow")
+				return &fakeDriver{name: opts.Required("address")}, nil
+			},
+			Probe:      func() error { return nil },
+			Standalone: true,
+		},
+	} {
+		if err := r.Register(reg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := newFakeRegistry(t)
+	if err := r.Register(fakeRegistration("a", nil)); err == nil {
+		t.Error("registering a duplicate name should fail")
+	}
+	if err := r.Register(fakeRegistration("", nil)); err == nil {
+		t.Error("registering without a name should fail")
+	}
+	if err := r.Register(&Registration{Name: "d"}); err == nil {
+		t.Error("registering without a constructor should fail")
+	}
+
+	got := []string{}
+	for _, reg := range r.Registrations() {
+		got = append(got, reg.Name)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c", "missing", "remote"}, got); diff != "" {
+		t.Errorf("Registrations() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRegistryNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		want    [][]string
+		wantErr string
+	}{
+		{
+			name:  "single",
+			input: []string{"a"},
+			want:  [][]string{{"a"}},
+		},
+		{
+			name:  "composite",
+			input: []string{"b", "a"},
+			want:  [][]string{{"b", "a"}},
+		},
+		{
+			name:  "standalone",
+			input: []string{"a", "remote?address=gaming-pc:5200", "b"},
+			want:  [][]string{{"a", "b"}, {"gaming-pc:5200"}},
+		},
+		{
+			name:  "standalone only",
+			input: []string{"remote?address=gaming-pc", "remote?address=build-pc&slow=true"},
+			want:  [][]string{{"gaming-pc"}, {"build-pc"}},
+		},
+		{
+			name:    "unknown option",
+			input:   []string{"remote?address=gaming-pc&port=5200"},
+			wantErr: "cannot use option 'port' of driver 'remote', the options are: address, slow",
+		},
+		{
+			name:    "missing option",
+			input:   []string{"remote"},
+			wantErr: "cannot use driver 'remote' without the 'address' option",
+		},
+		{
+			name:    "invalid option",
+			input:   []string{"remote?address=gaming-pc&slow=maybe"},
+			wantErr: "cannot parse option 'slow=maybe' of driver 'remote'",
+		},
+		{
+			name:    "unknown",
+			input:   []string{"a", "d"},
+			wantErr: "cannot find driver 'd', the registered drivers are: a, b, c, missing, remote",
+		},
+		{
+			name:    "conflict",
+			input:   []string{"a", "c"},
+			wantErr: "cannot use driver 'c' together with 'a'",
+		},
+		{
+			name:    "unavailable",
+			input:   []string{"missing"},
+			wantErr: "not installed",
+		},
+		{
+			name:    "empty",
+			input:   []string{},
+			wantErr: "no driver names",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ds, err := newFakeRegistry(t).New(tc.input...)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("New() error = %v, want '%s'", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := [][]string{}
+			for _, d := range ds {
+				mm, err := d.Get()
+				if err != nil {
+					t.Fatal(err)
+				}
+				names := []string{}
+				for _, device := range mm.GetDevice() {
+					names = append(names, device.GetName())
+				}
+				got = append(got, names)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegistryNewTarget(t *testing.T) {
+	r := newFakeRegistry(t)
+	d, err := r.NewTarget("remote?slow=true", "gaming-pc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mm, err := d.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mm.GetDevice()[0].GetName(); got != "gaming-pc" {
+		t.Errorf("got device '%s', want the target 'gaming-pc'", got)
+	}
+
+	for spec, wantErr := range map[string]string{
+		"a":                       "cannot probe with driver 'a', it does not read a remote machine",
+		"remote?address=build-pc": "cannot probe with driver 'remote' and the 'address' option",
+		"remote?slow=maybe":       "cannot parse option 'slow=maybe' of driver 'remote'",
+		"d":                       "cannot find driver 'd'",
+	} {
+		if _, err := r.NewTarget(spec, "gaming-pc"); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("NewTarget(%s) error = %v, want '%s'", spec, err, wantErr)
+		}
+	}
+	if err := r.CheckTarget("remote?slow=true"); err != nil {
+		t.Errorf("CheckTarget() = %v", err)
+	}
+	if err := r.CheckTarget("b"); err == nil {
+		t.Error("CheckTarget(b) should fail, it does not read a remote machine")
+	}
+}
+
+func TestNewByNameConflict(t *testing.T) {
+	for _, names := range [][]string{{"raspberrypi", "hwmon"}, {"hwmon", "raspberrypi"}, {"lmsensors", "raspberrypi"}} {
+		if _, err := NewByName(names...); err == nil || !strings.Contains(err.Error(), "they read the same sensors") {
+			t.Errorf("NewByName(%v) error = %v, want a conflict", names, err)
+		}
+	}
+	// hwmon and lmsensors report the same devices, which the composite driver keeps once.
+	if _, err := NewByName("hwmon", "lmsensors"); err != nil && strings.Contains(err.Error(), "they read the same sensors") {
+		t.Errorf("NewByName(hwmon, lmsensors) error = %v, want no conflict", err)
+	}
+}
+
+func TestRegistrations(t *testing.T) {
+	for _, name := range []string{"coretempremote", "coretempsdk", "hwmon", "ipmi", "ipmiremote", "lhm", "lmsensors", "raspberrypi", "redfish", "replay", "simulated", "thermal"} {
+		reg, ok := defaultRegistry.Lookup(name)
+		if !ok {
+			t.Errorf("driver '%s' is not registered", name)
+			continue
+		}
+		if reg.Description == "" {
+			t.Errorf("driver '%s' does not have a description", name)
+		}
+	}
+}
+
+func TestNewByName(t *testing.T) {
+	ds, err := NewByName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 1 || ds[0] == nil {
+		t.Errorf("expected the default driver, got %v", ds)
+	}
+	if _, err := NewByName("does-not-exist"); err == nil {
+		t.Error("an unknown driver should fail")
+	}
+}
+
+func TestNewByNameSimulated(t *testing.T) {
+	ds, err := NewByName("simulated?ambient=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mm, err := ds[0].Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mm.GetName() != "simulated" {
+		t.Errorf("name = '%s', want 'simulated' so it does not overwrite this machine", mm.GetName())
+	}
+	if temp := mm.GetDevice()[0].GetTemperature(); temp > 20 {
+		t.Errorf("temperature = %f°C, want close to the ambient temperature of 0°C", temp)
+	}
+
+	for _, spec := range []string{"simulated?sockets=0", "simulated?cores=0"} {
+		if _, err := NewByName(spec); err == nil {
+			t.Errorf("NewByName(%s) should fail", spec)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package relabel renames, ignores and calibrates the sensors reported by a driver, like the label, ignore and compute statements of sensors.conf.
+package relabel
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Rule changes the devices and readings that match Chip and Feature.
+//
+//	sensors:
+//	  - chip: "nct6775-*"
+//	    feature: "in1"
+//	    label: "+12V"
+//	    scale: 6.6
+//	  - chip: "acpitz-*"
+//	    ignore: true
+type Rule struct {
+	// Chip is a glob that matches the chip a device was read from, like "nct6775-*".
+	// Devices without a chip, like the CPUs reported by Core Temp, are matched by their name instead. An empty chip matches every device.
+	Chip string `yaml:"chip"`
+	// Feature is a glob that matches the label of a fan, voltage rail or sensor reading, like "temp1" or "fan*", as reported by the driver.
+	// An empty feature matches the whole device.
+	Feature string `yaml:"feature"`
+	// Label replaces the label of the matching readings, or the name of the device if Feature is empty.
+	Label string `yaml:"label"`
+	// Ignore drops the matching readings, or the whole device if Feature is empty.
+	Ignore bool `yaml:"ignore"`
+	// Scale multiplies the matching values, it defaults to 1.
+	// A rule without a Feature only calibrates the temperatures of the device, fans, voltage rails and other readings are calibrated by a rule with their Feature so units are never mixed.
+	// The per-core temperatures of a CPU are calibrated together with their limits and the core alarms are raised again against the calibrated limits.
+	// They are labelled by their core number and cannot be renamed or matched by a Feature.
+	Scale *float64 `yaml:"scale"`
+	// Offset is added to the matching values after they are scaled.
+	Offset float64 `yaml:"offset"`
+}
+
+// Rules are applied in order. Every rule is matched against the labels reported by the driver so renaming a reading does not change which rules apply to it.
+type Rules []*Rule
+
+// Validate returns an error if a rule is empty or has an invalid glob.
+func (r Rules) Validate() error {
+	for i, rule := range r {
+		if rule == nil {
+			return fmt.Errorf("cannot use sensor rule %d, it is empty", i)
+		}
+		if _, err := path.Match(rule.Chip, ""); err != nil {
+			return fmt.Errorf("cannot parse chip '%s' of sensor rule %d, err= %w", rule.Chip, i, err)
+		}
+		if _, err := path.Match(rule.Feature, ""); err != nil {
+			return fmt.Errorf("cannot parse feature '%s' of sensor rule %d, err= %w", rule.Feature, i, err)
+		}
+	}
+	return nil
+}
+
+// New creates a driver that applies the rules to everything that d returns.
+func New(d common.Driver, rules Rules) common.Driver {
+	return &relabelDriver{
+		driver: common.WithContext(d),
+		rules:  rules,
+	}
+}
+
+type relabelDriver struct {
+	driver common.ContextDriver
+	rules  Rules
+}
+
+func (d *relabelDriver) Get() (*pb.MachineMetrics, error) {
+	mm, err := d.driver.Get()
+	return d.rules.Apply(mm), err
+}
+
+func (d *relabelDriver) GetContext(ctx context.Context) (*pb.MachineMetrics, error) {
+	mm, err := d.driver.GetContext(ctx)
+	return d.rules.Apply(mm), err
+}
+
+// Apply returns a copy of mm with the rules applied. mm is not modified so a driver can return the same metrics more than once.
+func (r Rules) Apply(mm *pb.MachineMetrics) *pb.MachineMetrics {
+	if mm == nil || len(r) == 0 {
+		return mm
+	}
+	result := proto.Clone(mm).(*pb.MachineMetrics)
+	devices := []*pb.DeviceMetrics{}
+	for _, device := range result.GetDevice() {
+		if r.applyDevice(device) {
+			devices = append(devices, device)
+		}
+	}
+	result.Device = devices
+	return result
+}
+
+// applyDevice changes the device in place and returns false if it should be dropped.
+func (r Rules) applyDevice(device *pb.DeviceMetrics) bool {
+	chip := device.GetChip()
+	if chip == "" {
+		chip = device.GetName()
+	}
+
+	for _, rule := range r {
+		if rule == nil || !match(rule.Chip, chip) || rule.Feature != "" {
+			continue
+		}
+		if rule.Ignore {
+			return false
+		}
+		if rule.Label != "" {
+			device.Name = rule.Label
+		}
+		calibrateDevice(device, rule)
+	}
+
+	if fan := device.GetFan(); fan != nil {
+		label := fan.GetLabel()
+		for _, rule := range r.features(chip, label) {
+			if rule.Ignore {
+				return false
+			}
+			if rule.Label != "" {
+				fan.Label = rule.Label
+			}
+			calibrateFan(fan, rule)
+		}
+	}
+
+	if voltage := device.GetVoltage(); voltage != nil {
+		label := voltage.GetLabel()
+		for _, rule := range r.features(chip, label) {
+			if rule.Ignore {
+				return false
+			}
+			if rule.Label != "" {
+				voltage.Label = rule.Label
+			}
+			calibrateVoltage(voltage, rule)
+		}
+	}
+
+	if len(device.GetSensor()) > 0 {
+		readings := []*pb.SensorReading{}
+		for _, reading := range device.GetSensor() {
+			if applyReading(r.features(chip, reading.GetLabel()), reading) {
+				readings = append(readings, reading)
+			}
+		}
+		if len(readings) == 0 {
+			return false
+		}
+		device.Sensor = readings
+	}
+	return true
+}
+
+// features returns the rules that match a reading of a chip.
+func (r Rules) features(chip string, label string) Rules {
+	result := Rules{}
+	for _, rule := range r {
+		if rule != nil && rule.Feature != "" && match(rule.Chip, chip) && match(rule.Feature, label) {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+func applyReading(rules Rules, reading *pb.SensorReading) bool {
+	for _, rule := range rules {
+		if rule.Ignore {
+			return false
+		}
+		if rule.Label != "" {
+			reading.Label = rule.Label
+		}
+		calibrateReading(reading, rule)
+	}
+	return true
+}
+
+// calibrateDevice calibrates every temperature of the device. Temperatures that are not known are 0 and left as is.
+func calibrateDevice(device *pb.DeviceMetrics, rule *Rule) {
+	device.Temperature = rule.calibrateIfSet(device.Temperature)
+	if cpu := device.GetCpu(); cpu != nil {
+		for i := range cpu.Temperature {
+			cpu.Temperature[i] = rule.calibrate(cpu.Temperature[i])
+		}
+		for i := range cpu.CcdTemperature {
+			cpu.CcdTemperature[i] = rule.calibrate(cpu.CcdTemperature[i])
+		}
+		for i := range cpu.TemperatureMax {
+			cpu.TemperatureMax[i] = rule.calibrateIfSet(cpu.TemperatureMax[i])
+		}
+		for i := range cpu.TemperatureCrit {
+			cpu.TemperatureCrit[i] = rule.calibrateIfSet(cpu.TemperatureCrit[i])
+		}
+		cpu.PackageTemperature = rule.calibrateIfSet(cpu.PackageTemperature)
+		if rule.Scale != nil || rule.Offset != 0 {
+			recomputeAlarms(cpu)
+		}
+	}
+	if storage := device.GetStorage(); storage != nil {
+		for i := range storage.SensorTemperature {
+			storage.SensorTemperature[i] = rule.calibrate(storage.SensorTemperature[i])
+		}
+		storage.TemperatureWarning = rule.calibrateIfSet(storage.TemperatureWarning)
+		storage.TemperatureCrit = rule.calibrateIfSet(storage.TemperatureCrit)
+	}
+	for _, reading := range device.GetSensor() {
+		if reading.GetType() == "temp" {
+			calibrateReading(reading, rule)
+		}
+	}
+}
+
+// recomputeAlarms raises the alarm of every core that reached its calibrated limit, so the alarm agrees with the temperature and headroom that are exported.
+// Cores without a known limit keep the alarm reported by the driver.
+func recomputeAlarms(cpu *pb.CpuDeviceMetrics) {
+	for i := range cpu.TemperatureAlarm {
+		if i >= len(cpu.Temperature) {
+			break
+		}
+		limit := float64(0)
+		if i < len(cpu.TemperatureMax) && cpu.TemperatureMax[i] > 0 {
+			limit = cpu.TemperatureMax[i]
+		}
+		if i < len(cpu.TemperatureCrit) && cpu.TemperatureCrit[i] > 0 && (limit == 0 || cpu.TemperatureCrit[i] < limit) {
+			limit = cpu.TemperatureCrit[i]
+		}
+		if limit > 0 {
+			cpu.TemperatureAlarm[i] = cpu.Temperature[i] >= limit
+		}
+	}
+}
+
+func calibrateFan(fan *pb.FanDeviceMetrics, rule *Rule) {
+	fan.Rpm = rule.calibrate(fan.Rpm)
+	fan.MinRpm = rule.calibrateIfSet(fan.MinRpm)
+}
+
+func calibrateVoltage(voltage *pb.VoltageDeviceMetrics, rule *Rule) {
+	voltage.Volts = rule.calibrate(voltage.Volts)
+	voltage.MinVolts = rule.calibrateIfSet(voltage.MinVolts)
+	voltage.MaxVolts = rule.calibrateIfSet(voltage.MaxVolts)
+}
+
+func calibrateReading(reading *pb.SensorReading, rule *Rule) {
+	reading.Value = rule.calibrate(reading.Value)
+	reading.Min = rule.calibrateIfSet(reading.Min)
+	reading.Max = rule.calibrateIfSet(reading.Max)
+	reading.Crit = rule.calibrateIfSet(reading.Crit)
+}
+
+// calibrate applies the scale and offset of the rule to a value.
+func (rule *Rule) calibrate(value float64) float64 {
+	scale := float64(1)
+	if rule.Scale != nil {
+		scale = *rule.Scale
+	}
+	return value*scale + rule.Offset
+}
+
+// calibrateIfSet applies the scale and offset of the rule to a value that may not be set, like a limit. Values that are not set are 0 and left as is.
+func (rule *Rule) calibrateIfSet(value float64) float64 {
+	if value == 0 {
+		return value
+	}
+	return rule.calibrate(value)
+}
+
+func match(pattern string, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
@@ -0,0 +1,282 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relabel
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func scale(v float64) *float64 {
+	return &v
+}
+
+func testMetrics() *pb.MachineMetrics {
+	return &pb.MachineMetrics{
+		Name: "machine-name",
+		Device: []*pb.DeviceMetrics{
+			{
+				Name:        "Intel(R) Core(TM) i5-8259U CPU @ 2.30GHz",
+				Kind:        "cpu",
+				Temperature: 45,
+				Cpu: &pb.CpuDeviceMetrics{
+					Temperature:        []float64{44, 46},
+					PackageTemperature: 47,
+					TemperatureCrit:    []float64{100, 100},
+				},
+			},
+			{
+				Name:    "nct6775-isa-0290",
+				Kind:    "voltage",
+				Chip:    "nct6775-isa-0290",
+				Voltage: &pb.VoltageDeviceMetrics{Label: "in1", Volts: 1.824, MaxVolts: 2},
+			},
+			{
+				Name: "nct6775-isa-0290",
+				Kind: "fan",
+				Chip: "nct6775-isa-0290",
+				Fan:  &pb.FanDeviceMetrics{Label: "fan2", Rpm: 1146, MinRpm: 300},
+			},
+			{
+				Name: "nct6775-isa-0290",
+				Kind: "sensor",
+				Chip: "nct6775-isa-0290",
+				Sensor: []*pb.SensorReading{
+					{Label: "AUXTIN0", Type: "temp", Unit: "C", Value: -62},
+					{Label: "SYSTIN", Type: "temp", Unit: "C", Value: 33, Max: 80},
+					{Label: "power1", Type: "power", Unit: "W", Value: 120},
+				},
+			},
+			{
+				Name: "acpitz-acpi-0",
+				Kind: "sensor",
+				Chip: "acpitz-acpi-0",
+				Sensor: []*pb.SensorReading{
+					{Label: "temp1", Type: "temp", Unit: "C", Value: 40},
+				},
+			},
+		},
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		want  func(mm *pb.MachineMetrics)
+	}{
+		{
+			name:  "no rules",
+			rules: Rules{},
+			want:  func(mm *pb.MachineMetrics) {},
+		},
+		{
+			name: "rename and scale a voltage",
+			rules: Rules{
+				{Chip: "nct6775-*", Feature: "in1", Label: "+12V", Scale: scale(6.6)},
+			},
+			want: func(mm *pb.MachineMetrics) {
+				mm.Device[1].Voltage = &pb.VoltageDeviceMetrics{Label: "+12V", Volts: 1.824 * 6.6, MaxVolts: 2 * 6.6}
+			},
+		},
+		{
+			name: "rules match the original label",
+			rules: Rules{
+				{Chip: "nct6775-*", Feature: "SYSTIN", Label: "Motherboard"},
+				{Chip: "nct6775-*", Feature: "SYSTIN", Offset: -2},
+				{Chip: "nct6775-*", Feature: "Motherboard", Ignore: true},
+			},
+			want: func(mm *pb.MachineMetrics) {
+				mm.Device[3].Sensor[1] = &pb.SensorReading{Label: "Motherboard", Type: "temp", Unit: "C", Value: 31, Max: 78}
+			},
+		},
+		{
+			name: "ignore readings and devices",
+			rules: Rules{
+				{Chip: "nct6775-*", Feature: "AUXTIN*", Ignore: true},
+				{Chip: "nct6775-*", Feature: "fan*", Ignore: true},
+				{Chip: "acpitz-*", Ignore: true},
+			},
+			want: func(mm *pb.MachineMetrics) {
+				mm.Device[3].Sensor = mm.Device[3].Sensor[1:]
+				mm.Device = []*pb.DeviceMetrics{mm.Device[0], mm.Device[1], mm.Device[3]}
+			},
+		},
+		{
+			name: "ignoring every reading drops the device",
+			rules: Rules{
+				{Chip: "acpitz-*", Feature: "*", Ignore: true},
+			},
+			want: func(mm *pb.MachineMetrics) {
+				mm.Device = mm.Device[:4]
+			},
+		},
+		{
+			name: "device without a chip matches by name",
+			rules: Rules{
+				{Chip: "Intel*", Label: "CPU", Offset: 10},
+			},
+			want: func(mm *pb.MachineMetrics) {
+				mm.Device[0].Name = "CPU"
+				mm.Device[0].Temperature = 55
+				mm.Device[0].Cpu = &pb.CpuDeviceMetrics{
+					Temperature:        []float64{54, 56},
+					PackageTemperature: 57,
+					TemperatureCrit:    []float64{110, 110},
+				}
+			},
+		},
+		{
+			name: "chip offset only calibrates temperatures",
+			rules: Rules{
+				{Chip: "nct6775-*", Offset: -3},
+			},
+			want: func(mm *pb.MachineMetrics) {
+				// The fan, the voltage rail and the power reading are not temperatures and the devices do not have a temperature.
+				mm.Device[3].Sensor[0].Value = -65
+				mm.Device[3].Sensor[1] = &pb.SensorReading{Label: "SYSTIN", Type: "temp", Unit: "C", Value: 30, Max: 77}
+			},
+		},
+		{
+			name: "chip scale only calibrates temperatures",
+			rules: Rules{
+				{Chip: "nct6775-*", Scale: scale(2)},
+			},
+			want: func(mm *pb.MachineMetrics) {
+				mm.Device[3].Sensor[0].Value = -124
+				mm.Device[3].Sensor[1] = &pb.SensorReading{Label: "SYSTIN", Type: "temp", Unit: "C", Value: 66, Max: 160}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := testMetrics()
+			want := testMetrics()
+			tc.want(want)
+			got := tc.rules.Apply(input)
+			if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("Apply() mismatch (-want +got):\n%s", diff)
+			}
+			if !proto.Equal(input, testMetrics()) {
+				t.Error("Apply() modified its input")
+			}
+		})
+	}
+}
+
+func TestApplyRecomputesAlarms(t *testing.T) {
+	input := &pb.MachineMetrics{
+		Device: []*pb.DeviceMetrics{
+			{
+				Name: "k10temp-pci-00c3",
+				Kind: "cpu",
+				Chip: "k10temp-pci-00c3",
+				Cpu: &pb.CpuDeviceMetrics{
+					Temperature:      []float64{95, 85, 70},
+					TemperatureMax:   []float64{90, 90},
+					TemperatureCrit:  []float64{0, 100},
+					TemperatureAlarm: []bool{false, true, true},
+				},
+			},
+		},
+	}
+	want := &pb.MachineMetrics{
+		Device: []*pb.DeviceMetrics{
+			{
+				Name: "k10temp-pci-00c3",
+				Kind: "cpu",
+				Chip: "k10temp-pci-00c3",
+				Cpu: &pb.CpuDeviceMetrics{
+					Temperature:     []float64{100, 90, 75},
+					TemperatureMax:  []float64{95, 95},
+					TemperatureCrit: []float64{0, 105},
+					// The third core does not have a limit and keeps the alarm of the driver.
+					TemperatureAlarm: []bool{true, false, true},
+				},
+			},
+		},
+	}
+	rules := Rules{{Chip: "k10temp-*", Offset: 5}}
+	if diff := cmp.Diff(want, rules.Apply(input), protocmp.Transform()); diff != "" {
+		t.Errorf("Apply() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Rules{{Chip: "nct6775-*", Feature: "temp[12]"}}).Validate(); err != nil {
+		t.Errorf("expected valid rules, got %s", err)
+	}
+	if err := (Rules{{Chip: "nct6775-["}}).Validate(); err == nil {
+		t.Error("expected an error for an invalid chip glob")
+	}
+	if err := (Rules{{Feature: "temp["}}).Validate(); err == nil {
+		t.Error("expected an error for an invalid feature glob")
+	}
+	if err := (Rules{{Chip: "nct6775-*"}, nil}).Validate(); err == nil || !strings.Contains(err.Error(), "sensor rule 1") {
+		t.Errorf("expected an error for the empty rule 1, got %v", err)
+	}
+}
+
+func TestApplySkipsEmptyRules(t *testing.T) {
+	want := testMetrics()
+	want.Device[2].Fan.Label = "CPU Fan"
+	got := Rules{nil, {Chip: "nct6775-*", Feature: "fan2", Label: "CPU Fan"}, nil}.Apply(testMetrics())
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Apply() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+type fakeDriver struct {
+	mm  *pb.MachineMetrics
+	err error
+}
+
+func (d *fakeDriver) Get() (*pb.MachineMetrics, error) {
+	return d.mm, d.err
+}
+
+func TestNew(t *testing.T) {
+	errFake := errors.New("fake")
+	mm := testMetrics()
+	d := New(&fakeDriver{mm: mm, err: errFake}, Rules{{Chip: "nct6775-*", Feature: "in1", Scale: scale(2)}})
+
+	// The driver returns the same metrics every time, they must only be scaled once.
+	for i := 0; i < 2; i++ {
+		got, err := d.(common.ContextDriver).GetContext(context.Background())
+		if !errors.Is(err, errFake) {
+			t.Errorf("expected error %s, got %s", errFake, err)
+		}
+		if volts := got.GetDevice()[1].GetVoltage().GetVolts(); volts != 1.824*2 {
+			t.Errorf("expected %f V, got %f V", 1.824*2, volts)
+		}
+	}
+
+	got, err := New(&fakeDriver{}, Rules{{Ignore: true}}).Get()
+	if got != nil || err != nil {
+		t.Errorf("expected (nil, nil), got (%v, %v)", got, err)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replay plays back an ndjson log written with -log as if it were read from live sensors.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxLineSize is the longest record that can be read, machines with many sensors write lines longer than the 64KiB default of bufio.Scanner.
+const maxLineSize = 4 * 1024 * 1024
+
+// Options controls how a log is played back.
+type Options struct {
+	// Realtime returns the last record that is due, using the time between the original timestamps, like a sensor that is read whenever the exporter polls.
+	// A record is returned again when the exporter polls more often than the log was written, and records are skipped when it polls less often.
+	// Without Realtime every call to Get() returns the next record.
+	Realtime bool
+	// Speed multiplies how fast time passes in Realtime mode, 2 plays the log twice as fast. It defaults to 1.
+	Speed float64
+	// Loop starts from the beginning of the log after the last record instead of returning an error.
+	Loop bool
+	// Now replaces the timestamp of every record with the current time so the sinks see fresh data.
+	Now bool
+	// Name replaces the name of every record, like a log of this machine that is replayed next to its live sensors. The recorded names are kept without it.
+	Name string
+}
+
+// New creates a driver that returns one record of the ndjson log at name for each call to Get().
+func New(name string, opts Options) common.Driver {
+	return newDriver(name, opts, time.Now)
+}
+
+func newDriver(name string, opts Options, now func() time.Time) *replayDriver {
+	if opts.Speed <= 0 {
+		opts.Speed = 1
+	}
+	return &replayDriver{
+		name: name,
+		opts: opts,
+		now:  now,
+	}
+}
+
+type replayDriver struct {
+	name string
+	opts Options
+	now  func() time.Time
+
+	mu      sync.Mutex
+	fp      *os.File
+	scanner *bufio.Scanner
+	line    int
+	// records is the number of records read since the log was opened, a log without records cannot be looped.
+	records int
+	// next is a record that was read ahead to check if it is due.
+	next *pb.MachineMetrics
+	// current is the last record returned in Realtime mode.
+	current *pb.MachineMetrics
+	// start is when the first record of this pass was returned and first is its original timestamp.
+	start time.Time
+	first time.Time
+}
+
+func (d *replayDriver) Get() (*pb.MachineMetrics, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var mm *pb.MachineMetrics
+	var err error
+	if d.opts.Realtime {
+		mm, err = d.realtime()
+	} else {
+		mm, err = d.read()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A record can be returned more than once in Realtime mode.
+	mm = proto.Clone(mm).(*pb.MachineMetrics)
+	if d.opts.Now {
+		mm.Timestamp = timestamppb.New(d.now())
+	}
+	if d.opts.Name != "" {
+		mm.Name = d.opts.Name
+	}
+	return mm, nil
+}
+
+// realtime returns the last record that is due. The next pass of a loop starts once the last record has been returned.
+func (d *replayDriver) realtime() (*pb.MachineMetrics, error) {
+	now := d.now()
+	if d.current != nil {
+		next, err := d.peek()
+		if err != nil {
+			return nil, err
+		}
+		if next == nil {
+			d.current = nil
+		}
+	}
+	if d.current == nil {
+		mm, err := d.read()
+		if err != nil {
+			return nil, err
+		}
+		d.current = mm
+		d.start = now
+		d.first = mm.GetTimestamp().AsTime()
+	}
+
+	for {
+		next, err := d.peek()
+		if err != nil {
+			return nil, err
+		}
+		if next == nil || d.due(next).After(now) {
+			return d.current, nil
+		}
+		d.next = nil
+		d.current = next
+	}
+}
+
+// due returns when a record should be returned in Realtime mode.
+func (d *replayDriver) due(mm *pb.MachineMetrics) time.Time {
+	elapsed := mm.GetTimestamp().AsTime().Sub(d.first)
+	return d.start.Add(time.Duration(float64(elapsed) / d.opts.Speed))
+}
+
+// read returns the next record, starting over at the end of the log if Loop is set.
+func (d *replayDriver) read() (*pb.MachineMetrics, error) {
+	mm, err := d.peek()
+	if err != nil {
+		return nil, err
+	}
+	if mm == nil && d.opts.Loop && d.records > 0 {
+		d.rewind()
+		mm, err = d.peek()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if mm == nil {
+		return nil, fmt.Errorf("cannot read '%s', there are no more records after line %d", d.name, d.line)
+	}
+	d.next = nil
+	return mm, nil
+}
+
+// peek returns the next record without consuming it, or nil at the end of the log.
+func (d *replayDriver) peek() (*pb.MachineMetrics, error) {
+	if d.next != nil {
+		return d.next, nil
+	}
+	if d.scanner == nil {
+		fp, err := os.Open(d.name)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open '%s', err= %w", d.name, err)
+		}
+		d.fp = fp
+		d.scanner = bufio.NewScanner(fp)
+		d.scanner.Buffer(nil, maxLineSize)
+	}
+
+	for d.scanner.Scan() {
+		d.line++
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		mm := &pb.MachineMetrics{}
+		if err := protojson.Unmarshal(line, mm); err != nil {
+			return nil, fmt.Errorf("cannot read line '%s:%d', err= %w", d.name, d.line, err)
+		}
+		d.records++
+		d.next = mm
+		return mm, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read '%s', err= %w", d.name, err)
+	}
+	return nil, nil
+}
+
+// rewind closes the log so the next read starts from the first record.
+func (d *replayDriver) rewind() {
+	if d.fp != nil {
+		d.fp.Close()
+	}
+	d.fp = nil
+	d.scanner = nil
+	d.line = 0
+	d.records = 0
+	d.next = nil
+}
@@ -0,0 +1,224 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+const replayLog = "testdata/replay.ndjson"
+
+var startTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// fakeClock only moves forward when a test advances it.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func newTestDriver(name string, opts Options) (*replayDriver, *fakeClock) {
+	clock := &fakeClock{t: startTime}
+	return newDriver(name, opts, clock.now), clock
+}
+
+// temperatures calls Get() n times and returns the temperature of the first device of each record.
+func temperatures(t *testing.T, d *replayDriver, n int) []float64 {
+	t.Helper()
+	result := []float64{}
+	for i := 0; i < n; i++ {
+		mm, err := d.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, mm.GetDevice()[0].GetTemperature())
+	}
+	return result
+}
+
+func TestGet(t *testing.T) {
+	d, _ := newTestDriver(replayLog, Options{})
+	if diff := cmp.Diff([]float64{40, 45, 50}, temperatures(t, d, 3)); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err := d.Get()
+	if err == nil || !strings.Contains(err.Error(), "no more records") {
+		t.Errorf("Get() after the last record should fail, got %v", err)
+	}
+}
+
+func TestGetLoop(t *testing.T) {
+	d, _ := newTestDriver(replayLog, Options{Loop: true})
+	if diff := cmp.Diff([]float64{40, 45, 50, 40, 45, 50, 40}, temperatures(t, d, 7)); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetNow(t *testing.T) {
+	d, _ := newTestDriver(replayLog, Options{Now: true})
+	mm, err := d.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mm.GetTimestamp().AsTime(); !got.Equal(startTime) {
+		t.Errorf("timestamp = %s, want %s", got, startTime)
+	}
+	if mm.GetName() != "server" {
+		t.Errorf("name = '%s', want 'server'", mm.GetName())
+	}
+}
+
+func TestGetName(t *testing.T) {
+	d, _ := newTestDriver(replayLog, Options{Name: "replayed-server"})
+	mm, err := d.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mm.GetName() != "replayed-server" {
+		t.Errorf("name = '%s', want 'replayed-server'", mm.GetName())
+	}
+}
+
+func TestGetRealtime(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		// polls are the times, since the first call, that Get() is called at.
+		polls   []time.Duration
+		want    []float64
+		wantErr bool
+	}{
+		{
+			name:  "original speed",
+			opts:  Options{Realtime: true},
+			polls: []time.Duration{0, 500 * time.Millisecond, time.Second, 1500 * time.Millisecond, 2 * time.Second},
+			want:  []float64{40, 40, 45, 45, 50},
+		},
+		{
+			name:  "double speed",
+			opts:  Options{Realtime: true, Speed: 2},
+			polls: []time.Duration{0, 500 * time.Millisecond, time.Second},
+			want:  []float64{40, 45, 50},
+		},
+		{
+			name:  "skips overdue records",
+			opts:  Options{Realtime: true},
+			polls: []time.Duration{0, 2500 * time.Millisecond},
+			want:  []float64{40, 50},
+		},
+		{
+			name:  "loop starts a new pass after the last record",
+			opts:  Options{Realtime: true, Loop: true},
+			polls: []time.Duration{0, time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second},
+			want:  []float64{40, 45, 50, 40, 45},
+		},
+		{
+			name:    "ends after the last record",
+			opts:    Options{Realtime: true},
+			polls:   []time.Duration{0, 2 * time.Second, 3 * time.Second},
+			want:    []float64{40, 50},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, clock := newTestDriver(replayLog, tc.opts)
+			got := []float64{}
+			var err error
+			for _, poll := range tc.polls {
+				clock.t = startTime.Add(poll)
+				var mm *pb.MachineMetrics
+				mm, err = d.Get()
+				if err != nil {
+					break
+				}
+				got = append(got, mm.GetDevice()[0].GetTemperature())
+			}
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Get() error = %v, want error %t", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetRealtimeCopies(t *testing.T) {
+	d, _ := newTestDriver(replayLog, Options{Realtime: true})
+	first := temperatures(t, d, 1)
+	mm, err := d.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Changing a returned record must not change the next time it is returned.
+	mm.GetDevice()[0].Temperature = 0
+	if diff := cmp.Diff(first, temperatures(t, d, 1)); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.ndjson")
+	if err := os.WriteFile(invalid, []byte("{\"name\":\"server\"}\nnot json\n"), 0664); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty.ndjson")
+	if err := os.WriteFile(empty, []byte("\n"), 0664); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		calls   int
+		wantErr string
+	}{
+		{name: "missing", input: filepath.Join(dir, "missing.ndjson"), calls: 1, wantErr: "cannot open"},
+		{name: "invalid", input: invalid, calls: 2, wantErr: "invalid.ndjson:2"},
+		{name: "empty", input: empty, calls: 1, wantErr: "no more records"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, _ := newTestDriver(tc.input, Options{Loop: true})
+			var err error
+			for i := 0; i < tc.calls; i++ {
+				_, err = d.Get()
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Get() error = %v, want '%s'", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package simulated makes up believable sensor readings for demos and load tests.
+//
+// Every core is heated by a synthetic load and cools towards the ambient temperature following a first-order thermal model.
+// A run is reproducible, the same Config and seed always produce the same readings.
+package simulated
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Profile is the synthetic load that drives the simulation.
+type Profile string
+
+const (
+	// Idle keeps every core at a few percent of load.
+	Idle Profile = "idle"
+	// Bursty idles with random bursts of full load on some of the cores.
+	Bursty Profile = "bursty"
+	// Sustained keeps every core close to full load.
+	Sustained Profile = "sustained"
+	// Throttling runs every core at full load with poor cooling so the CPUs reach TjMax and lower their frequency.
+	Throttling Profile = "throttling"
+)
+
+const (
+	// idleHeat and loadHeat are how many degrees above ambient a core settles at when idle and the extra degrees at full load.
+	idleHeat = 10
+	loadHeat = 55
+	// throttlingHeat multiplies loadHeat in the Throttling profile, enough to go over TjMax.
+	throttlingHeat = 1.6
+	// throttleMargin is how far below TjMax the CPU starts to lower its frequency.
+	throttleMargin  = 5
+	minFrequencyHz  = 800 * 1000 * 1000
+	maxFrequencyHz  = 3600 * 1000 * 1000
+	fsbFrequencyMhz = 100
+	// The CPU draws idleWatts plus coreWatts for every core at full load and full frequency.
+	idleWatts = 10
+	coreWatts = 6
+	minFanRPM = 600
+	maxFanRPM = 2400
+)
+
+// DefaultName is the name of a simulated machine without a Name, it is not the hostname so it is not mistaken for the machine the exporter runs on.
+const DefaultName = "simulated"
+
+// Config describes the simulated machine.
+type Config struct {
+	// Name is the name of the machine, it defaults to DefaultName.
+	Name string
+	// Sockets is the number of physical CPUs, it defaults to 1.
+	Sockets int
+	// Cores is the number of cores of each CPU, it defaults to 4.
+	Cores int
+	// Fans is the number of fans, they speed up with the hottest CPU.
+	Fans int
+	// Profile is the load of the CPUs, it defaults to Idle.
+	Profile Profile
+	// Ambient is the temperature the CPUs cool down to, it defaults to 25°C when nil. 0°C is a valid ambient temperature.
+	Ambient *float64
+	// Noise is the standard deviation, in °C, of the noise added to every temperature reading.
+	Noise float64
+	// TjMax is the temperature the CPUs throttle at, it defaults to 100°C.
+	TjMax float64
+	// TimeConstant is how fast a core heats up and cools down, it reaches about 63% of the way to its new temperature after TimeConstant. It defaults to 10s.
+	TimeConstant time.Duration
+	// Step is how much simulated time passes between calls to Get(), it defaults to 1s.
+	// It is independent of the wall clock so the readings do not depend on how often the driver is polled.
+	Step time.Duration
+	// Seed seeds the load, the noise and the differences between cores.
+	Seed int64
+}
+
+// Validate returns an error if the topology or the profile is invalid.
+func (c Config) Validate() error {
+	if c.Sockets < 0 || c.Cores < 0 || c.Fans < 0 {
+		return fmt.Errorf("cannot simulate %d sockets with %d cores and %d fans, the counts cannot be negative", c.Sockets, c.Cores, c.Fans)
+	}
+	switch c.Profile {
+	case "", Idle, Bursty, Sustained, Throttling:
+	default:
+		return fmt.Errorf("cannot simulate profile '%s', the profiles are %s, %s, %s and %s", c.Profile, Idle, Bursty, Sustained, Throttling)
+	}
+	if c.Noise < 0 {
+		return fmt.Errorf("cannot simulate noise %f, it cannot be negative", c.Noise)
+	}
+	return nil
+}
+
+func (c Config) withDefaults() Config {
+	if c.Name == "" {
+		c.Name = DefaultName
+	}
+	if c.Sockets == 0 {
+		c.Sockets = 1
+	}
+	if c.Cores == 0 {
+		c.Cores = 4
+	}
+	if c.Profile == "" {
+		c.Profile = Idle
+	}
+	if c.Ambient == nil {
+		ambient := float64(25)
+		c.Ambient = &ambient
+	}
+	if c.TjMax == 0 {
+		c.TjMax = 100
+	}
+	if c.TimeConstant <= 0 {
+		c.TimeConstant = 10 * time.Second
+	}
+	if c.Step <= 0 {
+		c.Step = time.Second
+	}
+	return c
+}
+
+// New creates a driver that simulates the machine described by cfg. Get() returns an error if cfg is not valid.
+func New(cfg Config) common.Driver {
+	if err := cfg.Validate(); err != nil {
+		return common.NotSupported(err)
+	}
+	cfg = cfg.withDefaults()
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	sockets := make([]*socket, cfg.Sockets)
+	for i := range sockets {
+		s := &socket{
+			cores:     make([]*core, cfg.Cores),
+			frequency: 1,
+		}
+		for j := range s.cores {
+			s.cores[j] = &core{
+				// Every core has a slightly different cooler contact.
+				offset:      rng.Float64()*4 - 2,
+				temperature: *cfg.Ambient,
+			}
+		}
+		sockets[i] = s
+	}
+	return &simulatedDriver{
+		cfg:     cfg,
+		rng:     rng,
+		sockets: sockets,
+	}
+}
+
+type simulatedDriver struct {
+	cfg Config
+
+	mu      sync.Mutex
+	rng     *rand.Rand
+	sockets []*socket
+}
+
+type socket struct {
+	cores []*core
+	// frequency is the fraction of the maximum frequency the CPU allows, it drops while the CPU throttles.
+	frequency float64
+	joules    float64
+}
+
+type core struct {
+	offset      float64
+	temperature float64
+	load        float64
+	// burst is the number of steps left in a burst of the Bursty profile.
+	burst int
+}
+
+func (d *simulatedDriver) Get() (*pb.MachineMetrics, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dt := d.cfg.Step.Seconds()
+	// The fraction of the way to the steady state temperature that a core covers in one step.
+	alpha := 1 - math.Exp(-dt/d.cfg.TimeConstant.Seconds())
+	heat := float64(loadHeat)
+	if d.cfg.Profile == Throttling {
+		heat *= throttlingHeat
+	}
+
+	mm := &pb.MachineMetrics{
+		Name:      d.cfg.Name,
+		Timestamp: timestamppb.Now(),
+		Device:    []*pb.DeviceMetrics{},
+	}
+	hottest := *d.cfg.Ambient
+	for i, s := range d.sockets {
+		cpuMetrics := &pb.CpuDeviceMetrics{
+			Load:               make([]int32, len(s.cores)),
+			Temperature:        make([]float64, len(s.cores)),
+			NumCores:           int32(len(s.cores)),
+			FsbFrequencyMhz:    fsbFrequencyMhz,
+			CoreFrequencyHz:    make([]float64, len(s.cores)),
+			CoreFrequencyMinHz: make([]float64, len(s.cores)),
+			CoreFrequencyMaxHz: make([]float64, len(s.cores)),
+			Socket:             int32(i),
+			TemperatureMax:     make([]float64, len(s.cores)),
+			TemperatureCrit:    make([]float64, len(s.cores)),
+			TemperatureAlarm:   make([]bool, len(s.cores)),
+			TdpWatts:           idleWatts + coreWatts*float64(len(s.cores)),
+		}
+
+		watts := float64(idleWatts)
+		for j, c := range s.cores {
+			c.load = d.nextLoad(c)
+			// Power, and so heat, grows with both the load and the frequency.
+			work := c.load * s.frequency
+			steady := *d.cfg.Ambient + idleHeat + c.offset + heat*work
+			c.temperature += (steady - c.temperature) * alpha
+			watts += coreWatts * work
+
+			temperature := c.temperature + d.rng.NormFloat64()*d.cfg.Noise
+			cpuMetrics.Load[j] = int32(math.Round(c.load * 100))
+			cpuMetrics.Temperature[j] = temperature
+			cpuMetrics.CoreFrequencyHz[j] = minFrequencyHz + (maxFrequencyHz-minFrequencyHz)*work
+			cpuMetrics.CoreFrequencyMinHz[j] = minFrequencyHz
+			cpuMetrics.CoreFrequencyMaxHz[j] = maxFrequencyHz
+			cpuMetrics.TemperatureMax[j] = d.cfg.TjMax
+			cpuMetrics.TemperatureCrit[j] = d.cfg.TjMax
+			cpuMetrics.TemperatureAlarm[j] = temperature >= d.cfg.TjMax
+			hottest = math.Max(hottest, temperature)
+		}
+		s.joules += watts * dt
+		cpuMetrics.PackagePowerWatts = watts
+		cpuMetrics.PackageEnergyJoules = s.joules
+		cpuMetrics.PackageTemperature = maxFloat64(cpuMetrics.Temperature)
+		cpuMetrics.FrequencyMhz = common.Average(cpuMetrics.CoreFrequencyHz) / 1000 / 1000
+		s.throttle(cpuMetrics.PackageTemperature, d.cfg.TjMax)
+
+		mm.Device = append(mm.Device, &pb.DeviceMetrics{
+			Name:        "Simulated CPU",
+			Kind:        "cpu",
+			Temperature: common.Average(cpuMetrics.Temperature),
+			Cpu:         cpuMetrics,
+		})
+	}
+
+	// The fans spin up between 40°C and 10°C below TjMax.
+	speed := clamp((hottest-40)/(d.cfg.TjMax-50), 0, 1)
+	for i := 0; i < d.cfg.Fans; i++ {
+		rpm := minFanRPM + (maxFanRPM-minFanRPM)*speed + d.rng.NormFloat64()*10
+		mm.Device = append(mm.Device, &pb.DeviceMetrics{
+			Name: "simulated",
+			Kind: "fan",
+			Chip: "simulated",
+			Fan: &pb.FanDeviceMetrics{
+				Label:      fmt.Sprintf("fan%d", i+1),
+				Rpm:        math.Round(math.Max(rpm, 0)),
+				MinRpm:     minFanRPM / 2,
+				PwmPercent: math.Round(speed * 100),
+			},
+		})
+	}
+	return mm, nil
+}
+
+// nextLoad returns the load of a core, between 0 and 1, for the next step of the profile.
+func (d *simulatedDriver) nextLoad(c *core) float64 {
+	switch d.cfg.Profile {
+	case Sustained:
+		return 0.9 + d.rng.Float64()*0.1
+	case Throttling:
+		return 1
+	case Bursty:
+		if c.burst == 0 && d.rng.Float64() < 0.1 {
+			c.burst = 3 + d.rng.Intn(12)
+		}
+		if c.burst > 0 {
+			c.burst--
+			return 0.8 + d.rng.Float64()*0.2
+		}
+	}
+	return 0.02 + d.rng.Float64()*0.06
+}
+
+// throttle lowers the frequency of the CPU when it gets close to TjMax and raises it back once it cools down.
+func (s *socket) throttle(temperature float64, tjMax float64) {
+	if temperature >= tjMax-throttleMargin {
+		s.frequency = math.Max(s.frequency-0.1, 0.4)
+	} else {
+		s.frequency = math.Min(s.frequency+0.05, 1)
+	}
+}
+
+func maxFloat64(val []float64) float64 {
+	result := float64(0.0)
+	for i, v := range val {
+		if i == 0 || v > result {
+			result = v
+		}
+	}
+	return result
+}
+
+func clamp(v float64, lo float64, hi float64) float64 {
+	return math.Max(lo, math.Min(v, hi))
+}
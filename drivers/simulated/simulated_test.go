@@ -0,0 +1,177 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulated
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// run calls Get() n times and returns the last result.
+func run(t *testing.T, d common.Driver, n int) *pb.MachineMetrics {
+	t.Helper()
+	var mm *pb.MachineMetrics
+	for i := 0; i < n; i++ {
+		var err error
+		mm, err = d.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return mm
+}
+
+func TestTopology(t *testing.T) {
+	mm := run(t, New(Config{Name: "sim", Sockets: 2, Cores: 128, Fans: 3}), 1)
+	if mm.GetName() != "sim" {
+		t.Errorf("name = '%s', want 'sim'", mm.GetName())
+	}
+
+	kinds := []string{}
+	for _, device := range mm.GetDevice() {
+		kinds = append(kinds, device.GetKind())
+	}
+	if diff := cmp.Diff([]string{"cpu", "cpu", "fan", "fan", "fan"}, kinds); diff != "" {
+		t.Errorf("devices mismatch (-want +got):\n%s", diff)
+	}
+	for i, device := range mm.GetDevice()[:2] {
+		cpu := device.GetCpu()
+		if cpu.GetSocket() != int32(i) {
+			t.Errorf("socket = %d, want %d", cpu.GetSocket(), i)
+		}
+		if cpu.GetNumCores() != 128 || len(cpu.GetTemperature()) != 128 || len(cpu.GetLoad()) != 128 || len(cpu.GetCoreFrequencyHz()) != 128 {
+			t.Errorf("socket %d should have 128 cores, got %v", i, cpu)
+		}
+	}
+	if got := mm.GetDevice()[4].GetFan().GetLabel(); got != "fan3" {
+		t.Errorf("label = '%s', want 'fan3'", got)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	cfg := Config{Name: "sim", Sockets: 2, Cores: 8, Fans: 2, Profile: Bursty, Noise: 1, Seed: 42}
+	a := New(cfg)
+	b := New(cfg)
+	ignore := protocmp.IgnoreFields(&pb.MachineMetrics{}, "timestamp")
+	for i := 0; i < 20; i++ {
+		if diff := cmp.Diff(run(t, a, 1), run(t, b, 1), protocmp.Transform(), ignore); diff != "" {
+			t.Fatalf("step %d of the same seed mismatch (-a +b):\n%s", i, diff)
+		}
+	}
+
+	cfg.Seed = 43
+	if diff := cmp.Diff(run(t, a, 1), run(t, New(cfg), 21), protocmp.Transform(), ignore); diff == "" {
+		t.Error("different seeds should produce different readings")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	tests := []struct {
+		profile         Profile
+		minTemperature  float64
+		maxTemperature  float64
+		maxFrequencyMhz float64
+		wantAlarm       bool
+	}{
+		// Idle settles at about ambient + 10°C.
+		{profile: Idle, minTemperature: 30, maxTemperature: 40, maxFrequencyMhz: 1100},
+		// Sustained settles at about ambient + 10°C + 55°C.
+		{profile: Sustained, minTemperature: 80, maxTemperature: 95, maxFrequencyMhz: 3600},
+		// Throttling hovers around TjMax at a lower frequency.
+		{profile: Throttling, minTemperature: 85, maxTemperature: 105, maxFrequencyMhz: 3500},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(string(tc.profile), func(t *testing.T) {
+			t.Parallel()
+
+			// 10 minutes of 1s steps is long enough to settle.
+			mm := run(t, New(Config{Cores: 4, Profile: tc.profile}), 600)
+			cpu := mm.GetDevice()[0]
+			if got := cpu.GetTemperature(); got < tc.minTemperature || got > tc.maxTemperature {
+				t.Errorf("temperature = %f, want between %f and %f", got, tc.minTemperature, tc.maxTemperature)
+			}
+			if got := cpu.GetCpu().GetFrequencyMhz(); got > tc.maxFrequencyMhz {
+				t.Errorf("frequency = %f MHz, want at most %f MHz", got, tc.maxFrequencyMhz)
+			}
+			if got := cpu.GetCpu().GetPackagePowerWatts(); got <= 0 || got > cpu.GetCpu().GetTdpWatts() {
+				t.Errorf("power = %f W, want between 0 and the TDP %f W", got, cpu.GetCpu().GetTdpWatts())
+			}
+		})
+	}
+}
+
+func TestBursty(t *testing.T) {
+	d := New(Config{Cores: 16, Profile: Bursty, Seed: 7})
+	idle, busy := 0, 0
+	for i := 0; i < 100; i++ {
+		for _, load := range run(t, d, 1).GetDevice()[0].GetCpu().GetLoad() {
+			if load >= 80 {
+				busy++
+			} else {
+				idle++
+			}
+		}
+	}
+	if idle == 0 || busy == 0 || busy > idle {
+		t.Errorf("bursty load should mostly idle with some bursts, got %d idle and %d busy readings", idle, busy)
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	mm := run(t, New(Config{}), 1)
+	if mm.GetName() != DefaultName {
+		t.Errorf("name = '%s', want '%s'", mm.GetName(), DefaultName)
+	}
+
+	// An explicit 0°C is not replaced by the default ambient temperature of 25°C.
+	freezing := float64(0)
+	cold := run(t, New(Config{Ambient: &freezing}), 1)
+	got := mm.GetDevice()[0].GetTemperature() - cold.GetDevice()[0].GetTemperature()
+	if math.Abs(got-25) > 0.001 {
+		t.Errorf("the default ambient is %f°C warmer than 0°C, want 25°C", got)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "profile", cfg: Config{Profile: "melting"}},
+		{name: "cores", cfg: Config{Cores: -1}},
+		{name: "noise", cfg: Config{Noise: -1}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tc.cfg.Validate(); err == nil {
+				t.Error("Validate() should fail")
+			}
+			if _, err := New(tc.cfg).Get(); err == nil {
+				t.Error("Get() should fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package thermal reads the thermal zones and cooling devices of the Linux thermal framework from sysfs.
+// Many ARM boards and laptops only report their temperatures there and not through hwmon.
+// See https://www.kernel.org/doc/html/latest/driver-api/thermal/sysfs-api.html
+package thermal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	"github.com/jeremyje/coretemp-exporter/drivers/lmsensors"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	thermalClassDir = "class/thermal"
+	zonePrefix      = "thermal_zone"
+	coolingPrefix   = "cooling_device"
+)
+
+var (
+	tripPointRegexp = regexp.MustCompile(`^trip_point_(\d+)_type$`)
+)
+
+// New creates a driver that reads the thermal zones and cooling devices from /sys.
+func New() common.Driver {
+	return NewWithRoot(lmsensors.DefaultSysfsRoot)
+}
+
+// NewWithRoot creates a driver that reads the thermal zones and cooling devices from a sysfs tree mounted at root.
+func NewWithRoot(root string) common.Driver {
+	return &thermalDriver{
+		root: root,
+	}
+}
+
+// Available returns true if there are thermal zones under /sys.
+func Available() bool {
+	return Probe() == nil
+}
+
+// Probe returns an error that explains why there are no thermal zones under /sys, or nil if there are.
+func Probe() error {
+	return probe(lmsensors.DefaultSysfsRoot)
+}
+
+func probe(root string) error {
+	zones, err := readZones(root)
+	if err != nil {
+		return err
+	}
+	if len(zones) == 0 {
+		return fmt.Errorf("cannot find any thermal zones in '%s'", filepath.Join(root, thermalClassDir))
+	}
+	return nil
+}
+
+type thermalDriver struct {
+	root string
+}
+
+func (d *thermalDriver) Get() (*pb.MachineMetrics, error) {
+	zones, err := readZones(d.root)
+	if err != nil {
+		return nil, err
+	}
+	coolingDevices, err := readCoolingDevices(d.root)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.MachineMetrics{
+		Name:      common.Hostname(),
+		Timestamp: timestamppb.Now(),
+		Device:    append(zones, coolingDevices...),
+	}, nil
+}
+
+// readZones reads every thermal zone that has a temperature. Zones of devices that are turned off, like a WiFi card, fail to read their temperature and are skipped.
+func readZones(root string) ([]*pb.DeviceMetrics, error) {
+	dirs, err := classEntries(root, zonePrefix)
+	if err != nil {
+		return nil, err
+	}
+	result := []*pb.DeviceMetrics{}
+	for _, dir := range dirs {
+		temp, ok := readFloat(filepath.Join(dir, "temp"))
+		if !ok {
+			continue
+		}
+		zone := &pb.ThermalZoneMetrics{
+			Zone:      filepath.Base(dir),
+			Type:      readString(filepath.Join(dir, "type")),
+			Policy:    readString(filepath.Join(dir, "policy")),
+			TripPoint: readTripPoints(dir),
+		}
+		result = append(result, &pb.DeviceMetrics{
+			Name:        zone.Type,
+			Kind:        "thermal_zone",
+			Chip:        zone.Zone,
+			Temperature: temp / 1000,
+			ThermalZone: zone,
+		})
+	}
+	return result, nil
+}
+
+func readTripPoints(dir string) []*pb.TripPoint {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	indexes := []int{}
+	for _, entry := range entries {
+		if m := tripPointRegexp.FindStringSubmatch(entry.Name()); m != nil {
+			index, _ := strconv.Atoi(m[1])
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+
+	result := []*pb.TripPoint{}
+	for _, index := range indexes {
+		prefix := filepath.Join(dir, fmt.Sprintf("trip_point_%d_", index))
+		temp, ok := readFloat(prefix + "temp")
+		if !ok {
+			continue
+		}
+		hyst, _ := readFloat(prefix + "hyst")
+		result = append(result, &pb.TripPoint{
+			Type:        readString(prefix + "type"),
+			Temperature: temp / 1000,
+			Hysteresis:  hyst / 1000,
+			Index:       int32(index),
+		})
+	}
+	return result
+}
+
+func readCoolingDevices(root string) ([]*pb.DeviceMetrics, error) {
+	dirs, err := classEntries(root, coolingPrefix)
+	if err != nil {
+		return nil, err
+	}
+	result := []*pb.DeviceMetrics{}
+	for _, dir := range dirs {
+		cur, ok := readInt(filepath.Join(dir, "cur_state"))
+		if !ok {
+			continue
+		}
+		maxState, _ := readInt(filepath.Join(dir, "max_state"))
+		device := &pb.CoolingDeviceMetrics{
+			Device:   filepath.Base(dir),
+			Type:     readString(filepath.Join(dir, "type")),
+			CurState: cur,
+			MaxState: maxState,
+		}
+		result = append(result, &pb.DeviceMetrics{
+			Name:          device.Type,
+			Kind:          "cooling_device",
+			Chip:          device.Device,
+			CoolingDevice: device,
+		})
+	}
+	return result, nil
+}
+
+// classEntries returns the directories in /sys/class/thermal that start with prefix, in the numeric order of the kernel so thermal_zone10 comes after thermal_zone2.
+func classEntries(root string, prefix string) ([]string, error) {
+	classDir := filepath.Join(root, thermalClassDir)
+	entries, err := os.ReadDir(classDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read '%s', err= %w", classDir, err)
+	}
+	type indexed struct {
+		index int
+		dir   string
+	}
+	found := []indexed{}
+	for _, entry := range entries {
+		index, err := strconv.Atoi(strings.TrimPrefix(entry.Name(), prefix))
+		if !strings.HasPrefix(entry.Name(), prefix) || err != nil {
+			continue
+		}
+		found = append(found, indexed{index: index, dir: filepath.Join(classDir, entry.Name())})
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].index < found[j].index
+	})
+	result := make([]string, len(found))
+	for i, f := range found {
+		result[i] = f.dir
+	}
+	return result, nil
+}
+
+func readInt(name string) (int64, bool) {
+	value, err := strconv.ParseInt(readString(name), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+func readFloat(name string) (float64, bool) {
+	value, err := strconv.ParseFloat(readString(name), 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+func readString(name string) string {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thermal
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const (
+	testSysfsRoot = "testdata/sys"
+)
+
+func ExampleNew() {
+	info, err := New().Get()
+	if err != nil {
+		fmt.Printf("ERROR: %s", err)
+	}
+	fmt.Printf("thermal: %+v", info)
+}
+
+func TestGet(t *testing.T) {
+	got, err := NewWithRoot(testSysfsRoot).Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetName() == "" || got.GetTimestamp() == nil {
+		t.Errorf("expected a name and a timestamp, got %v", got)
+	}
+
+	want := []*pb.DeviceMetrics{
+		{
+			Name:        "acpitz",
+			Kind:        "thermal_zone",
+			Chip:        "thermal_zone0",
+			Temperature: 44,
+			ThermalZone: &pb.ThermalZoneMetrics{
+				Zone:   "thermal_zone0",
+				Type:   "acpitz",
+				Policy: "step_wise",
+				TripPoint: []*pb.TripPoint{
+					{Type: "critical", Temperature: 119},
+					{Type: "active", Temperature: 60, Hysteresis: 2, Index: 1},
+				},
+			},
+		},
+		{
+			Name:        "x86_pkg_temp",
+			Kind:        "thermal_zone",
+			Chip:        "thermal_zone1",
+			Temperature: 51,
+			ThermalZone: &pb.ThermalZoneMetrics{
+				Zone:      "thermal_zone1",
+				Type:      "x86_pkg_temp",
+				Policy:    "user_space",
+				TripPoint: []*pb.TripPoint{{Type: "passive"}},
+			},
+		},
+		// thermal_zone2 does not have a temperature.
+		{
+			Name:        "cpu-thermal",
+			Kind:        "thermal_zone",
+			Chip:        "thermal_zone10",
+			Temperature: 62.3,
+			ThermalZone: &pb.ThermalZoneMetrics{
+				Zone:   "thermal_zone10",
+				Type:   "cpu-thermal",
+				Policy: "step_wise",
+				TripPoint: []*pb.TripPoint{
+					{Type: "passive", Temperature: 75, Hysteresis: 2},
+					{Type: "hot", Temperature: 85, Index: 2},
+					{Type: "critical", Temperature: 90, Index: 10},
+				},
+			},
+		},
+		{
+			Name:          "Processor",
+			Kind:          "cooling_device",
+			Chip:          "cooling_device0",
+			CoolingDevice: &pb.CoolingDeviceMetrics{Device: "cooling_device0", Type: "Processor", MaxState: 10},
+		},
+		{
+			Name:          "pwm-fan",
+			Kind:          "cooling_device",
+			Chip:          "cooling_device1",
+			CoolingDevice: &pb.CoolingDeviceMetrics{Device: "cooling_device1", Type: "pwm-fan", CurState: 2, MaxState: 4},
+		},
+		{
+			Name:          "intel_powerclamp",
+			Kind:          "cooling_device",
+			Chip:          "cooling_device2",
+			CoolingDevice: &pb.CoolingDeviceMetrics{Device: "cooling_device2", Type: "intel_powerclamp", CurState: -1, MaxState: 50},
+		},
+	}
+	if diff := cmp.Diff(want, got.GetDevice(), protocmp.Transform()); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProbe(t *testing.T) {
+	if err := probe(testSysfsRoot); err != nil {
+		t.Errorf("expected thermal zones in '%s', got %s", testSysfsRoot, err)
+	}
+	if err := probe(t.TempDir()); err == nil {
+		t.Error("expected an error without /sys/class/thermal")
+	}
+}
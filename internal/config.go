@@ -0,0 +1,96 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jeremyje/coretemp-exporter/drivers"
+	"github.com/jeremyje/coretemp-exporter/drivers/relabel"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the YAML configuration file of coretemp-exporter.
+type Config struct {
+	// Sensors renames, ignores and calibrates the sensors that are reported by the driver.
+	Sensors relabel.Rules `yaml:"sensors"`
+	// Probe lets Prometheus read remote machines through /probe, it is only served if it has a module.
+	Probe ProbeConfig `yaml:"probe"`
+}
+
+// ProbeConfig holds the modules of /probe, like the modules of the blackbox exporter.
+type ProbeConfig struct {
+	// Modules are selected by the module parameter, like /probe?module=bmc&target=10.0.0.5.
+	Modules map[string]*ProbeModule `yaml:"modules"`
+}
+
+// ProbeModule reads a fixed set of remote machines with one driver.
+type ProbeModule struct {
+	// Driver reads the targets, like "redfish?user=root&insecure=true". The target is passed as its address option.
+	Driver string `yaml:"driver"`
+	// Targets are the only targets the module reads, any other target is refused so /probe cannot be used to reach other machines.
+	Targets []string `yaml:"targets"`
+}
+
+// Validate returns an error if a module cannot probe its targets.
+func (c ProbeConfig) Validate() error {
+	for name, module := range c.Modules {
+		if module == nil {
+			return fmt.Errorf("probe module '%s' is empty", name)
+		}
+		if err := drivers.CheckTarget(module.Driver); err != nil {
+			return fmt.Errorf("invalid probe module '%s', err= %w", name, err)
+		}
+		if len(module.Targets) == 0 {
+			return fmt.Errorf("probe module '%s' does not have any targets", name)
+		}
+	}
+	return nil
+}
+
+func (m *ProbeModule) allows(target string) bool {
+	for _, t := range m.Targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+func loadConfig(name string) (*Config, error) {
+	if name == "" {
+		return &Config{}, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config '%s', err= %w", name, err)
+	}
+	return parseConfig(data)
+}
+
+func parseConfig(data []byte) (*Config, error) {
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("cannot parse config, err= %w", err)
+	}
+	if err := cfg.Sensors.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Probe.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jeremyje/coretemp-exporter/drivers/relabel"
+)
+
+var (
+	//go:embed testdata/config.yaml
+	configYAML []byte
+)
+
+func TestParseConfig(t *testing.T) {
+	got, err := parseConfig(configYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scale := 6.6
+	want := &Config{
+		Sensors: relabel.Rules{
+			{Chip: "nct6775-*", Feature: "in1", Label: "+12V", Scale: &scale},
+			{Chip: "nct6775-*", Feature: "SYSTIN", Label: "Motherboard", Offset: -2},
+			{Chip: "nct6775-*", Feature: "AUXTIN*", Ignore: true},
+			{Chip: "acpitz-*", Ignore: true},
+		},
+		Probe: ProbeConfig{
+			Modules: map[string]*ProbeModule{
+				"bmc":    {Driver: "redfish?user=root&insecure=true", Targets: []string{"10.0.0.5", "10.0.0.6"}},
+				"gaming": {Driver: "lhm", Targets: []string{"gaming-pc"}},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseConfig() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseConfigInvalid(t *testing.T) {
+	for _, input := range []string{
+		"sensors: [",
+		"sensors:\n  - chip: \"nct6775-[\"\n",
+		"sensors:\n  - feature: \"temp[\"\n",
+		"sensors:\n  -\n",
+		"probe:\n  modules:\n    bmc:\n",
+		"probe:\n  modules:\n    bmc:\n      driver: redfish\n",
+		"probe:\n  modules:\n    bmc:\n      driver: hwmon\n      targets: [\"10.0.0.5\"]\n",
+		"probe:\n  modules:\n    bmc:\n      driver: \"redfish?address=10.0.0.5\"\n      targets: [\"10.0.0.5\"]\n",
+		"probe:\n  modules:\n    bmc:\n      driver: \"redfish?password=calvin\"\n      targets: [\"10.0.0.5\"]\n",
+	} {
+		if _, err := parseConfig([]byte(input)); err == nil {
+			t.Errorf("expected an error for '%s'", input)
+		}
+	}
+}
+
+func TestLoadConfigEmpty(t *testing.T) {
+	cfg, err := loadConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Sensors) != 0 {
+		t.Errorf("expected no sensor rules, got %v", cfg.Sensors)
+	}
+	if _, err := loadConfig("testdata/does-not-exist.yaml"); err == nil {
+		t.Error("expected an error for a missing config")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jeremyje/coretemp-exporter/drivers"
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+)
+
+// ListDrivers prints every driver and its options, whether it is available on this machine and why not if it is not.
+func ListDrivers(w io.Writer) error {
+	return listDrivers(w, drivers.Registrations())
+}
+
+func listDrivers(w io.Writer, registrations []*drivers.Registration) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DRIVER\tSTATUS\tDESCRIPTION")
+	for _, reg := range registrations {
+		status := "available"
+		if err := reg.Probe(); err != nil {
+			status = fmt.Sprintf("not available: %s", err)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", reg.Name, status, reg.Description)
+		for _, opt := range reg.Options {
+			value := "required"
+			if opt.Default != "" {
+				value = "default " + opt.Default
+			}
+			fmt.Fprintf(tw, "  ?%s=\t%s\t%s\n", opt.Name, value, opt.Description)
+		}
+	}
+	return tw.Flush()
+}
+
+// newDrivers creates the drivers in args.Driver, or the default driver for this platform if there are none.
+func newDrivers(args *Args) ([]common.Driver, error) {
+	return drivers.NewByName(splitList(args.Driver)...)
+}
+
+// splitList splits a comma separated flag and drops the empty entries.
+func splitList(names string) []string {
+	result := []string{}
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jeremyje/coretemp-exporter/drivers"
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+)
+
+func TestListDrivers(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := listDrivers(buf, []*drivers.Registration{
+		{
+			Name:        "replay",
+			Description: "Plays back a log",
+			Options: []drivers.Option{
+				{Name: "file", Description: "Log to play back"},
+				{Name: "loop", Default: "false", Description: "Start over"},
+			},
+			New:   func(opts *drivers.Options) (common.Driver, error) { return nil, nil },
+			Probe: func() error { return nil },
+		},
+		{
+			Name:        "coretempsdk",
+			Description: "Core Temp",
+			New:         func(opts *drivers.Options) (common.Driver, error) { return nil, nil },
+			Probe:       func() error { return errors.New("not supported, only runs on windows") },
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `DRIVER       STATUS                                              DESCRIPTION
+replay       available                                           Plays back a log
+  ?file=     required                                            Log to play back
+  ?loop=     default false                                       Start over
+coretempsdk  not available: not supported, only runs on windows  Core Temp
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("listDrivers() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewDriversReplay(t *testing.T) {
+	ds, err := newDrivers(&Args{Driver: "replay?file=testdata/cputemps.ndjson&loop=true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 1 {
+		t.Fatalf("got %d drivers, want 1", len(ds))
+	}
+	mm, err := ds[0].Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mm.GetName() != "demo" {
+		t.Errorf("name = '%s', want 'demo'", mm.GetName())
+	}
+
+	for _, driver := range []string{"replay", "replay?file=testdata/cputemps.ndjson&speed=fast", "replay?file=testdata/cputemps.ndjson&rate=2"} {
+		if _, err := newDrivers(&Args{Driver: driver}); err == nil {
+			t.Errorf("-driver=%s should fail", driver)
+		}
+	}
+}
+
+func TestNewDriversSimulated(t *testing.T) {
+	ds, err := newDrivers(&Args{Driver: "simulated?profile=sustained&sockets=2&cores=8&fans=1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mm, err := ds[0].Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(mm.GetDevice()); got != 3 {
+		t.Errorf("got %d devices, want 2 CPUs and 1 fan", got)
+	}
+
+	// A simulation and a replay are separate machines.
+	ds, err = newDrivers(&Args{Driver: "simulated,replay?file=testdata/cputemps.ndjson"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 2 {
+		t.Errorf("got %d drivers, want 2", len(ds))
+	}
+
+	for _, driver := range []string{"simulated?profile=melting", "simulated?sockets=-1", "simulated?noise=loud"} {
+		if _, err := newDrivers(&Args{Driver: driver}); err == nil {
+			t.Errorf("-driver=%s should fail", driver)
+		}
+	}
+}
+
+func TestNewDrivers(t *testing.T) {
+	tests := []struct {
+		name string
+		args *Args
+		want int
+	}{
+		{name: "local", args: &Args{Driver: "simulated"}, want: 1},
+		{name: "remote only", args: &Args{Driver: "coretempremote?address=gaming-pc,coretempremote?address=build-pc:5201"}, want: 2},
+		{name: "local and remote", args: &Args{Driver: "simulated,coretempremote?address=gaming-pc"}, want: 2},
+		{name: "LibreHardwareMonitor only", args: &Args{Driver: "lhm?address=gaming-pc"}, want: 1},
+		{name: "Core Temp and LibreHardwareMonitor", args: &Args{Driver: "coretempremote?address=gaming-pc,lhm?address=http://build-pc:8085"}, want: 2},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newDrivers(tc.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tc.want {
+				t.Errorf("got %d drivers, want %d", len(got), tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "", want: []string{}},
+		{input: "hwmon", want: []string{"hwmon"}},
+		{input: "hwmon, lmsensors,", want: []string{"hwmon", "lmsensors"}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.want, splitList(tc.input)); diff != "" {
+				t.Errorf("splitList() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeremyje/coretemp-exporter/drivers"
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	"github.com/jeremyje/coretemp-exporter/drivers/relabel"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// scrapeTimeoutHeader is set by Prometheus to the scrape timeout of the job.
+const scrapeTimeoutHeader = "X-Prometheus-Scrape-Timeout-Seconds"
+
+var errProbeClosed = errors.New("cannot probe, the exporter is shutting down")
+
+// probeHandler reads a remote machine when /probe?module=&target= is scraped, like the blackbox exporter, so one exporter can cover a whole rack.
+// Only the targets of a module are read so /probe cannot be used to reach other machines or to send the credentials of a module elsewhere.
+// Every request has its own registry so the metrics of one target never show up in the scrape of another.
+// The driver of a target is kept between requests so its connection and HTTP client are reused, they are closed by Close.
+type probeHandler struct {
+	modules map[string]*ProbeModule
+	// newDriver creates the driver of a module for a target.
+	newDriver func(spec string, target string) (common.Driver, error)
+	rules     relabel.Rules
+	// timeout is used when Prometheus does not send its scrape timeout.
+	timeout time.Duration
+
+	mu sync.Mutex
+	// drivers are keyed by module and target, there is at most one for each target of every module.
+	drivers map[probeKey]*probeDriver
+	closed  bool
+}
+
+type probeKey struct {
+	module string
+	target string
+}
+
+type probeDriver struct {
+	// driver is kept to be closed, relabel and WithContext hide its Close method.
+	driver common.Driver
+	cd     common.ContextDriver
+}
+
+func newProbeHandler(cfg ProbeConfig, rules relabel.Rules, timeout time.Duration) *probeHandler {
+	return &probeHandler{
+		modules:   cfg.Modules,
+		newDriver: drivers.NewTarget,
+		rules:     rules,
+		timeout:   timeout,
+	}
+}
+
+func (h *probeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		http.Error(w, "the 'target' parameter is missing", http.StatusBadRequest)
+		return
+	}
+	name := r.URL.Query().Get("module")
+	module, ok := h.modules[name]
+	if !ok {
+		http.Error(w, fmt.Sprintf("cannot probe with module '%s', the modules are: %s", name, strings.Join(h.names(), ", ")), http.StatusBadRequest)
+		return
+	}
+	if !module.allows(target) {
+		http.Error(w, fmt.Sprintf("cannot probe '%s', it is not a target of module '%s'", target, name), http.StatusForbidden)
+		return
+	}
+	d, err := h.driver(name, module, target)
+	if errors.Is(err, errProbeClosed) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	timeout := h.timeout
+	if seconds, err := strconv.ParseFloat(r.Header.Get(scrapeTimeoutHeader), 64); err == nil && seconds > 0 {
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	registry := prometheus.NewRegistry()
+	sink, err := newRegistrySink(registry)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	probeSuccess := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "probe_success",
+		Help: "1 if the target was read, otherwise 0",
+	})
+	probeDuration := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "probe_duration_seconds",
+		Help: "Time it took to read the target in seconds",
+	})
+	registry.MustRegister(probeSuccess, probeDuration)
+
+	start := time.Now()
+	mm, err := d.GetContext(ctx)
+	probeDuration.Set(time.Since(start).Seconds())
+	if err != nil {
+		log.Printf("ERROR: cannot probe '%s' with module '%s': %s", target, name, err)
+	} else {
+		probeSuccess.Set(1)
+		sink.Observe(ctx, mm)
+	}
+
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
+
+// driver returns the driver of a target of module, it is created on the first probe of the target.
+func (h *probeHandler) driver(name string, module *ProbeModule, target string) (common.ContextDriver, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, errProbeClosed
+	}
+	key := probeKey{module: name, target: target}
+	if pd, ok := h.drivers[key]; ok {
+		return pd.cd, nil
+	}
+	d, err := h.newDriver(module.Driver, target)
+	if err != nil {
+		return nil, err
+	}
+	if h.drivers == nil {
+		h.drivers = map[probeKey]*probeDriver{}
+	}
+	pd := &probeDriver{
+		driver: d,
+		cd:     common.WithContext(relabel.New(d, h.rules)),
+	}
+	h.drivers[key] = pd
+	return pd.cd, nil
+}
+
+// Close closes the drivers of every target, later probes fail.
+func (h *probeHandler) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	ds := []common.Driver{}
+	for _, pd := range h.drivers {
+		ds = append(ds, pd.driver)
+	}
+	h.drivers = nil
+	closeDrivers(ds)
+}
+
+func (h *probeHandler) names() []string {
+	result := []string{}
+	for name := range h.modules {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,261 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	"github.com/jeremyje/coretemp-exporter/drivers/relabel"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+// newTestProbeHandler counts the drivers it creates in created, every driver it creates would send a request to its target.
+func newTestProbeHandler(release chan struct{}, created *int32) *probeHandler {
+	return &probeHandler{
+		modules: map[string]*ProbeModule{
+			"bmc":    {Driver: "redfish", Targets: []string{"10.0.0.5", "10.0.0.6"}},
+			"broken": {Driver: "broken", Targets: []string{"10.0.0.7"}},
+			"hung":   {Driver: "hung", Targets: []string{"10.0.0.8"}},
+		},
+		newDriver: func(spec string, target string) (common.Driver, error) {
+			atomic.AddInt32(created, 1)
+			switch spec {
+			case "redfish":
+				return &fakeDriver{mm: &pb.MachineMetrics{
+					Name: target,
+					Device: []*pb.DeviceMetrics{{
+						Name: "Computer System Chassis",
+						Kind: "sensor",
+						Chip: "redfish-System.Embedded.1",
+						Sensor: []*pb.SensorReading{
+							{Label: "System Board Inlet Temp", Type: "temp", Unit: "C", Value: 23},
+						},
+					}},
+				}}, nil
+			case "broken":
+				return &fakeDriver{err: errors.New("connection refused")}, nil
+			case "hung":
+				return &fakeDriver{release: release}, nil
+			}
+			return nil, fmt.Errorf("cannot find driver '%s'", spec)
+		},
+		rules:   relabel.Rules{{Chip: "redfish-*", Feature: "System Board Inlet Temp", Label: "Inlet"}},
+		timeout: time.Minute,
+	}
+}
+
+func probe(t *testing.T, h http.Handler, url string, header http.Header) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", url, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	h.ServeHTTP(rec, req)
+	body, err := io.ReadAll(rec.Result().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code, otelScopeRegexp.ReplaceAllString(string(body), "")
+}
+
+func TestProbe(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	tests := []struct {
+		name     string
+		url      string
+		header   http.Header
+		wantCode int
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "missing target",
+			url:      "/probe",
+			wantCode: http.StatusBadRequest,
+			want:     []string{"the 'target' parameter is missing"},
+		},
+		{
+			name:     "unknown module",
+			url:      "/probe?target=10.0.0.5&module=snmp",
+			wantCode: http.StatusBadRequest,
+			want:     []string{"cannot probe with module 'snmp', the modules are: bmc, broken, hung"},
+		},
+		{
+			name:     "missing module",
+			url:      "/probe?target=10.0.0.5",
+			wantCode: http.StatusBadRequest,
+			want:     []string{"cannot probe with module ''"},
+		},
+		{
+			name:     "unknown target",
+			url:      "/probe?target=169.254.169.254&module=bmc",
+			wantCode: http.StatusForbidden,
+			want:     []string{"cannot probe '169.254.169.254', it is not a target of module 'bmc'"},
+		},
+		{
+			name:     "target of another module",
+			url:      "/probe?target=10.0.0.7&module=bmc",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "target",
+			url:      "/probe?target=10.0.0.5&module=bmc",
+			wantCode: http.StatusOK,
+			want: []string{
+				`probe_success 1`,
+				`hardware_sensor_value{chip="redfish-System.Embedded.1",hostname="10.0.0.5",kind="sensor",label="Inlet",name="Computer System Chassis",type="temp"} 23`,
+			},
+			// Each probe has its own registry.
+			notWant: []string{`hostname="10.0.0.6"`, `go_goroutines`},
+		},
+		{
+			name:     "another target",
+			url:      "/probe?target=10.0.0.6&module=bmc",
+			wantCode: http.StatusOK,
+			want:     []string{`probe_success 1`, `hostname="10.0.0.6"`},
+			notWant:  []string{`hostname="10.0.0.5"`},
+		},
+		{
+			name:     "error",
+			url:      "/probe?target=10.0.0.7&module=broken",
+			wantCode: http.StatusOK,
+			want:     []string{`probe_success 0`},
+			notWant:  []string{`hardware_sensor_value`},
+		},
+		{
+			name:     "scrape timeout",
+			url:      "/probe?target=10.0.0.8&module=hung",
+			header:   http.Header{scrapeTimeoutHeader: []string{"0.05"}},
+			wantCode: http.StatusOK,
+			want:     []string{`probe_success 0`},
+		},
+	}
+
+	h := newTestProbeHandler(release, new(int32))
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			code, got := probe(t, h, tc.url, tc.header)
+			if code != tc.wantCode {
+				t.Errorf("expected status %d, got %d\n%s", tc.wantCode, code, got)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("probe does not contain '%s'\n%s", want, got)
+				}
+			}
+			for _, notWant := range tc.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("probe contains '%s'\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestProbeRefusedTarget(t *testing.T) {
+	created := new(int32)
+	h := newTestProbeHandler(nil, created)
+	for _, url := range []string{
+		"/probe?target=169.254.169.254&module=bmc",
+		"/probe?target=10.0.0.5:22&module=bmc",
+		"/probe?target=http://attacker.example.com&module=bmc",
+		"/probe?target=10.0.0.5&module=snmp",
+	} {
+		if code, _ := probe(t, h, url, nil); code < 400 || code >= 500 {
+			t.Errorf("%s: expected a 4xx status, got %d", url, code)
+		}
+	}
+	if got := atomic.LoadInt32(created); got != 0 {
+		t.Errorf("%d drivers were created for targets that are not allowed", got)
+	}
+}
+
+func TestProbeNotConfigured(t *testing.T) {
+	mux, _ := newServeMux(&Config{}, time.Second, http.NotFoundHandler())
+	if code, _ := probe(t, mux, "/probe?target=10.0.0.5&module=bmc", nil); code != http.StatusNotFound {
+		t.Errorf("expected /probe to be missing without probe modules, got status %d", code)
+	}
+	mux, _ = newServeMux(&Config{Probe: ProbeConfig{Modules: map[string]*ProbeModule{"bmc": {Driver: "redfish", Targets: []string{"10.0.0.5"}}}}}, time.Second, http.NotFoundHandler())
+	if code, _ := probe(t, mux, "/probe?target=10.0.0.6&module=bmc", nil); code != http.StatusForbidden {
+		t.Errorf("expected /probe to refuse an unknown target, got status %d", code)
+	}
+}
+
+type closingDriver struct {
+	fakeDriver
+	closed int32
+}
+
+func (d *closingDriver) Close() error {
+	atomic.AddInt32(&d.closed, 1)
+	return nil
+}
+
+func TestProbeReusesDrivers(t *testing.T) {
+	created := []*closingDriver{}
+	h := &probeHandler{
+		modules: map[string]*ProbeModule{
+			"remote": {Driver: "coretempremote", Targets: []string{"gaming-pc", "build-pc"}},
+		},
+		newDriver: func(spec string, target string) (common.Driver, error) {
+			d := &closingDriver{fakeDriver: fakeDriver{mm: &pb.MachineMetrics{Name: target}}}
+			created = append(created, d)
+			return d, nil
+		},
+		timeout: time.Minute,
+	}
+
+	for _, url := range []string{
+		"/probe?target=gaming-pc&module=remote",
+		"/probe?target=gaming-pc&module=remote",
+		"/probe?target=build-pc&module=remote",
+		"/probe?target=gaming-pc&module=remote",
+	} {
+		if code, got := probe(t, h, url, nil); code != http.StatusOK || !strings.Contains(got, "probe_success 1") {
+			t.Errorf("%s: expected a successful probe, got status %d\n%s", url, code, got)
+		}
+	}
+	if len(created) != 2 {
+		t.Fatalf("%d drivers were created, want one for each target", len(created))
+	}
+
+	h.Close()
+	for i, d := range created {
+		if got := atomic.LoadInt32(&d.closed); got != 1 {
+			t.Errorf("driver %d was closed %d times, want 1", i, got)
+		}
+	}
+	if code, _ := probe(t, h, "/probe?target=gaming-pc&module=remote", nil); code != http.StatusServiceUnavailable {
+		t.Errorf("expected a probe after Close to fail with status %d, got %d", http.StatusServiceUnavailable, code)
+	}
+	if len(created) != 2 {
+		t.Errorf("a driver was created after Close")
+	}
+}
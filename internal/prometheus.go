@@ -17,6 +17,8 @@ package internal
 import (
 	"context"
 	"net/http"
+	"sync"
+	"time"
 
 	pb "github.com/jeremyje/coretemp-exporter/proto"
 	"github.com/prometheus/client_golang/prometheus"
@@ -29,20 +31,97 @@ import (
 	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
 	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
 	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 )
 
 type metricsSink struct {
-	CPUCoreTemperature asyncfloat64.Gauge
-	CPUCoreLoad        asyncint64.Gauge
-	CPUInfoPollCount   syncfloat64.Counter
-	CPUFrequency       asyncfloat64.Gauge
-	CPUFSBFrequency    asyncfloat64.Gauge
-	lastValue          *pb.MachineMetrics
+	CPUCoreTemperature              asyncfloat64.Gauge
+	CPUPackageTemperature           asyncfloat64.Gauge
+	CPUCoreLoad                     asyncint64.Gauge
+	CPUInfoPollCount                syncfloat64.Counter
+	CPUFrequency                    asyncfloat64.Gauge
+	CPUFSBFrequency                 asyncfloat64.Gauge
+	CPUCoreFrequency                asyncfloat64.Gauge
+	CPUCoreFrequencyMin             asyncfloat64.Gauge
+	CPUCoreFrequencyMax             asyncfloat64.Gauge
+	CPUCoreTemperatureMax           asyncfloat64.Gauge
+	CPUCoreTemperatureCrit          asyncfloat64.Gauge
+	CPUCoreTemperatureAlarm         asyncint64.Gauge
+	CPUCoreTemperatureHeadroom      asyncfloat64.Gauge
+	CPUPower                        asyncfloat64.Gauge
+	CPUEnergy                       asyncfloat64.Counter
+	CPUTDP                          asyncfloat64.Gauge
+	CPUUnderVoltage                 asyncint64.Gauge
+	CPUUnderVoltageOccurred         asyncint64.Gauge
+	CPUFrequencyCapped              asyncint64.Gauge
+	CPUFrequencyCappedOccurred      asyncint64.Gauge
+	CPUThrottled                    asyncint64.Gauge
+	CPUThrottledOccurred            asyncint64.Gauge
+	CPUSoftTemperatureLimit         asyncint64.Gauge
+	CPUSoftTemperatureLimitOccurred asyncint64.Gauge
+	DeviceTemperature               asyncfloat64.Gauge
+	DeviceTemperatureWarning        asyncfloat64.Gauge
+	DeviceTemperatureCrit           asyncfloat64.Gauge
+	HardwareSensor                  asyncfloat64.Gauge
+	FanSpeed                        asyncfloat64.Gauge
+	Voltage                         asyncfloat64.Gauge
+	ThermalZoneTripPoint            asyncfloat64.Gauge
+	CoolingDeviceState              asyncint64.Gauge
+	CoolingDeviceMaxState           asyncint64.Gauge
+	MachineUp                       asyncint64.Gauge
+	MachineLastSuccess              asyncfloat64.Gauge
+	PollErrors                      syncint64.Counter
+	// maxAge is how long the metrics of a machine are exported without a new reading, they never expire if it is 0.
+	maxAge time.Duration
+	now    func() time.Time
+	// lastValue holds the latest state of each machine, there is more than one when remote machines are read.
+	mu        sync.Mutex
+	lastValue map[string]*machineState
+}
+
+// machineState is the last reading of a machine. The time it was received is used instead of its timestamp because a replay keeps the timestamps of its log.
+type machineState struct {
+	// mm is nil once the machine is down so its last reading is no longer exported.
+	mm          *pb.MachineMetrics
+	lastSuccess time.Time
+}
+
+func (m *metricsSink) ObserveError(ctx context.Context, kind string) {
+	m.PollErrors.Add(ctx, 1, attribute.Key("kind").String(kind))
+}
+
+// ObserveDown stops exporting the last reading of the machine name because its driver failed.
+func (m *metricsSink) ObserveDown(ctx context.Context, name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if state, ok := m.lastValue[name]; ok {
+		state.mm = nil
+	}
 }
 
 func (m *metricsSink) ObserveAsync(ctx context.Context) {
-	m.Observe(ctx, m.lastValue)
+	now := m.now()
+	m.mu.Lock()
+	values := make(map[string]machineState, len(m.lastValue))
+	for name, state := range m.lastValue {
+		if m.maxAge > 0 && now.Sub(state.lastSuccess) > m.maxAge {
+			state.mm = nil
+		}
+		values[name] = *state
+	}
+	m.mu.Unlock()
+
+	for name, state := range values {
+		hostAttrs := attribute.Key("hostname").String(name)
+		m.MachineLastSuccess.Observe(ctx, float64(state.lastSuccess.UnixNano())/float64(time.Second), hostAttrs)
+		if state.mm == nil {
+			m.MachineUp.Observe(ctx, 0, hostAttrs)
+			continue
+		}
+		m.MachineUp.Observe(ctx, 1, hostAttrs)
+		m.observe(ctx, state.mm)
+	}
 }
 
 func (m *metricsSink) Observe(ctx context.Context, mm *pb.MachineMetrics) {
@@ -50,8 +129,14 @@ func (m *metricsSink) Observe(ctx context.Context, mm *pb.MachineMetrics) {
 		return
 	}
 
-	m.lastValue = mm
+	m.mu.Lock()
+	m.lastValue[mm.GetName()] = &machineState{mm: mm, lastSuccess: m.now()}
+	m.mu.Unlock()
+
+	m.observe(ctx, mm)
+}
 
+func (m *metricsSink) observe(ctx context.Context, mm *pb.MachineMetrics) {
 	for _, device := range mm.GetDevice() {
 		attrs := []attribute.KeyValue{
 			attribute.Key("hostname").String(mm.GetName()),
@@ -60,32 +145,180 @@ func (m *metricsSink) Observe(ctx context.Context, mm *pb.MachineMetrics) {
 		}
 		curAttrs := attrs
 
+		if device.GetStorage() != nil {
+			storageMetrics := device.GetStorage()
+			curAttrs = withAttrs(attrs, attribute.Key("device").String(storageMetrics.GetDevice()))
+			if storageMetrics.GetTemperatureWarning() > 0 {
+				m.DeviceTemperatureWarning.Observe(ctx, storageMetrics.GetTemperatureWarning(), curAttrs...)
+			}
+			if storageMetrics.GetTemperatureCrit() > 0 {
+				m.DeviceTemperatureCrit.Observe(ctx, storageMetrics.GetTemperatureCrit(), curAttrs...)
+			}
+		}
+
+		if device.GetThermalZone() != nil {
+			zoneMetrics := device.GetThermalZone()
+			curAttrs = withAttrs(attrs, attribute.Key("zone").String(zoneMetrics.GetZone()))
+			for _, trip := range zoneMetrics.GetTripPoint() {
+				m.ThermalZoneTripPoint.Observe(ctx, trip.GetTemperature(), withAttrs(
+					curAttrs,
+					attribute.Int("trip", int(trip.GetIndex())),
+					attribute.Key("type").String(trip.GetType()),
+				)...)
+			}
+		}
+
+		// CPUs report their temperature per socket and core in the cpu_ metrics instead.
+		if device.GetCpu() == nil && device.GetTemperature() != 0 {
+			m.DeviceTemperature.Observe(ctx, device.GetTemperature(), curAttrs...)
+		}
+
 		if device.GetCpu() != nil {
 			cpuMetrics := device.GetCpu()
+			curAttrs = withAttrs(attrs, attribute.Int("socket", int(cpuMetrics.GetSocket())))
 			for core, tempC := range cpuMetrics.GetTemperature() {
-				m.CPUCoreTemperature.Observe(ctx, tempC, append(curAttrs, attribute.Int("core", core))...)
+				m.CPUCoreTemperature.Observe(ctx, tempC, withAttrs(curAttrs, attribute.Int("core", core))...)
+			}
+			if cpuMetrics.GetPackageTemperature() > 0 {
+				m.CPUPackageTemperature.Observe(ctx, cpuMetrics.GetPackageTemperature(), curAttrs...)
 			}
 			m.CPUInfoPollCount.Add(ctx, 1, curAttrs...)
 
-			m.CPUFrequency.Observe(ctx, cpuMetrics.GetFrequencyMhz()*1000*1000, append(
+			m.CPUFrequency.Observe(ctx, cpuMetrics.GetFrequencyMhz()*1000*1000, withAttrs(
 				curAttrs,
 				attribute.Int("core_count", int(cpuMetrics.GetNumCores())),
 			)...)
 
-			m.CPUFSBFrequency.Observe(ctx, cpuMetrics.GetFsbFrequencyMhz()*1000*1000, append(
+			m.CPUFSBFrequency.Observe(ctx, cpuMetrics.GetFsbFrequencyMhz()*1000*1000, withAttrs(
 				curAttrs,
 				attribute.Int("core_count", int(cpuMetrics.GetNumCores())),
 			)...)
 
 			for core, load := range cpuMetrics.GetLoad() {
-				m.CPUCoreLoad.Observe(ctx, int64(load), append(curAttrs, attribute.Int("core", core))...)
+				m.CPUCoreLoad.Observe(ctx, int64(load), withAttrs(curAttrs, attribute.Int("core", core))...)
+			}
+
+			for core, freq := range cpuMetrics.GetCoreFrequencyHz() {
+				m.CPUCoreFrequency.Observe(ctx, freq, withAttrs(curAttrs, attribute.Int("core", core))...)
+			}
+			for core, freq := range cpuMetrics.GetCoreFrequencyMinHz() {
+				m.CPUCoreFrequencyMin.Observe(ctx, freq, withAttrs(curAttrs, attribute.Int("core", core))...)
+			}
+			for core, freq := range cpuMetrics.GetCoreFrequencyMaxHz() {
+				m.CPUCoreFrequencyMax.Observe(ctx, freq, withAttrs(curAttrs, attribute.Int("core", core))...)
+			}
+
+			temps := cpuMetrics.GetTemperature()
+			for core, tempC := range cpuMetrics.GetTemperatureMax() {
+				if tempC > 0 {
+					m.CPUCoreTemperatureMax.Observe(ctx, tempC, withAttrs(curAttrs, attribute.Int("core", core))...)
+				}
+			}
+			for core, tempC := range cpuMetrics.GetTemperatureCrit() {
+				if tempC <= 0 {
+					continue
+				}
+				coreAttrs := withAttrs(curAttrs, attribute.Int("core", core))
+				m.CPUCoreTemperatureCrit.Observe(ctx, tempC, coreAttrs...)
+				if core < len(temps) {
+					m.CPUCoreTemperatureHeadroom.Observe(ctx, tempC-temps[core], coreAttrs...)
+				}
+			}
+			for core, alarm := range cpuMetrics.GetTemperatureAlarm() {
+				value := int64(0)
+				if alarm {
+					value = 1
+				}
+				m.CPUCoreTemperatureAlarm.Observe(ctx, value, withAttrs(curAttrs, attribute.Int("core", core))...)
+			}
+
+			for _, power := range []struct {
+				domain string
+				watts  float64
+				joules float64
+			}{
+				{domain: "package", watts: cpuMetrics.GetPackagePowerWatts(), joules: cpuMetrics.GetPackageEnergyJoules()},
+				{domain: "core", watts: cpuMetrics.GetCorePowerWatts(), joules: cpuMetrics.GetCoreEnergyJoules()},
+				{domain: "dram", watts: cpuMetrics.GetDramPowerWatts(), joules: cpuMetrics.GetDramEnergyJoules()},
+			} {
+				domainAttrs := withAttrs(curAttrs, attribute.Key("domain").String(power.domain))
+				if power.watts > 0 {
+					m.CPUPower.Observe(ctx, power.watts, domainAttrs...)
+				}
+				if power.joules > 0 {
+					m.CPUEnergy.Observe(ctx, power.joules, domainAttrs...)
+				}
+			}
+			if cpuMetrics.GetTdpWatts() > 0 {
+				m.CPUTDP.Observe(ctx, cpuMetrics.GetTdpWatts(), curAttrs...)
+			}
+
+			if device.GetThrottled() != nil {
+				throttled := device.GetThrottled()
+				for _, flag := range []struct {
+					gauge asyncint64.Gauge
+					value bool
+				}{
+					{gauge: m.CPUUnderVoltage, value: throttled.GetUnderVoltage()},
+					{gauge: m.CPUUnderVoltageOccurred, value: throttled.GetUnderVoltageOccurred()},
+					{gauge: m.CPUFrequencyCapped, value: throttled.GetFrequencyCapped()},
+					{gauge: m.CPUFrequencyCappedOccurred, value: throttled.GetFrequencyCappedOccurred()},
+					{gauge: m.CPUThrottled, value: throttled.GetThrottled()},
+					{gauge: m.CPUThrottledOccurred, value: throttled.GetThrottledOccurred()},
+					{gauge: m.CPUSoftTemperatureLimit, value: throttled.GetSoftTemperatureLimit()},
+					{gauge: m.CPUSoftTemperatureLimitOccurred, value: throttled.GetSoftTemperatureLimitOccurred()},
+				} {
+					flag.gauge.Observe(ctx, boolValue(flag.value), curAttrs...)
+				}
 			}
 		}
+
+		for _, sensor := range device.GetSensor() {
+			m.HardwareSensor.Observe(ctx, sensor.GetValue(), withAttrs(
+				attrs,
+				attribute.Key("chip").String(device.GetChip()),
+				attribute.Key("label").String(sensor.GetLabel()),
+				attribute.Key("type").String(sensor.GetType()),
+			)...)
+		}
+
+		if device.GetFan() != nil {
+			fanMetrics := device.GetFan()
+			m.FanSpeed.Observe(ctx, fanMetrics.GetRpm(), withAttrs(attrs, attribute.Key("label").String(fanMetrics.GetLabel()))...)
+		}
+
+		if device.GetVoltage() != nil {
+			voltageMetrics := device.GetVoltage()
+			m.Voltage.Observe(ctx, voltageMetrics.GetVolts(), withAttrs(attrs, attribute.Key("label").String(voltageMetrics.GetLabel()))...)
+		}
+
+		if device.GetCoolingDevice() != nil {
+			coolingMetrics := device.GetCoolingDevice()
+			coolingAttrs := withAttrs(attrs, attribute.Key("device").String(coolingMetrics.GetDevice()))
+			m.CoolingDeviceState.Observe(ctx, coolingMetrics.GetCurState(), coolingAttrs...)
+			m.CoolingDeviceMaxState.Observe(ctx, coolingMetrics.GetMaxState(), coolingAttrs...)
+		}
 	}
 
 }
 
-func newMetricsSink(ctx context.Context) (*metricsSink, http.Handler, error) {
+// boolValue returns 1 for true and 0 for false.
+func boolValue(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// withAttrs returns a copy of attrs with more attributes so that observations never share a backing array.
+func withAttrs(attrs []attribute.KeyValue, more ...attribute.KeyValue) []attribute.KeyValue {
+	result := make([]attribute.KeyValue, 0, len(attrs)+len(more))
+	result = append(result, attrs...)
+	return append(result, more...)
+}
+
+// newMetricsSink creates the metrics sink of /metrics. A machine that has not been read for maxAge is reported down.
+func newMetricsSink(ctx context.Context, maxAge time.Duration) (*metricsSink, http.Handler, error) {
 	registry := prometheus.NewRegistry()
 	registry.Register(collectors.NewBuildInfoCollector())
 	registry.Register(collectors.NewGoCollector())
@@ -93,26 +326,36 @@ func newMetricsSink(ctx context.Context) (*metricsSink, http.Handler, error) {
 		ReportErrors: true,
 	}))
 
-	prometheusExporter, err := otelprom.New(otelprom.WithRegisterer(registry))
-	if err != nil {
-		return nil, nil, err
-	}
-	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(prometheusExporter))
-	meter := provider.Meter("github.com/jeremyje/coretemp-exporter")
-	sink, err := newMetrics(meter)
+	sink, err := newRegistrySink(registry)
 	if err != nil {
 		return nil, nil, err
 	}
+	sink.maxAge = maxAge
 
 	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
 	return sink, h, nil
 }
 
+// newRegistrySink creates a metrics sink that is exported through registry.
+func newRegistrySink(registry prometheus.Registerer) (*metricsSink, error) {
+	prometheusExporter, err := otelprom.New(otelprom.WithRegisterer(registry))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(prometheusExporter))
+	meter := provider.Meter("github.com/jeremyje/coretemp-exporter")
+	return newMetrics(meter)
+}
+
 func newMetrics(meter metric.Meter) (*metricsSink, error) {
 	cpuCoreTemperature, err := meter.AsyncFloat64().Gauge("cpu_core_temperature", instrument.WithDescription("Temperature of a CPU Core in Celcius"), instrument.WithUnit("C"))
 	if err != nil {
 		return nil, err
 	}
+	cpuPackageTemperature, err := meter.AsyncFloat64().Gauge("cpu_package_temperature", instrument.WithDescription("Temperature of a CPU package in Celcius"), instrument.WithUnit("C"))
+	if err != nil {
+		return nil, err
+	}
 	cpuCoreLoad, err := meter.AsyncInt64().Gauge("cpu_core_load", instrument.WithDescription("CPU Load percentage (0-100)"), instrument.WithUnit("C"))
 	if err != nil {
 		return nil, err
@@ -129,16 +372,172 @@ func newMetrics(meter metric.Meter) (*metricsSink, error) {
 	if err != nil {
 		return nil, err
 	}
+	cpuCoreFrequency, err := meter.AsyncFloat64().Gauge("cpu_core_frequency_hertz", instrument.WithDescription("Current clock frequency of a CPU Core in Hz"))
+	if err != nil {
+		return nil, err
+	}
+	cpuCoreFrequencyMin, err := meter.AsyncFloat64().Gauge("cpu_core_frequency_min_hertz", instrument.WithDescription("Lowest allowed clock frequency of a CPU Core in Hz"))
+	if err != nil {
+		return nil, err
+	}
+	cpuCoreFrequencyMax, err := meter.AsyncFloat64().Gauge("cpu_core_frequency_max_hertz", instrument.WithDescription("Highest allowed clock frequency of a CPU Core in Hz"))
+	if err != nil {
+		return nil, err
+	}
+	cpuCoreTemperatureMax, err := meter.AsyncFloat64().Gauge("cpu_core_temperature_max", instrument.WithDescription("High temperature threshold of a CPU Core in Celcius"), instrument.WithUnit("C"))
+	if err != nil {
+		return nil, err
+	}
+	cpuCoreTemperatureCrit, err := meter.AsyncFloat64().Gauge("cpu_core_temperature_crit", instrument.WithDescription("Critical temperature (TjMax) of a CPU Core in Celcius"), instrument.WithUnit("C"))
+	if err != nil {
+		return nil, err
+	}
+	cpuCoreTemperatureAlarm, err := meter.AsyncInt64().Gauge("cpu_core_temperature_alarm", instrument.WithDescription("1 if a CPU Core has reached one of its temperature thresholds, otherwise 0"))
+	if err != nil {
+		return nil, err
+	}
+	cpuCoreTemperatureHeadroom, err := meter.AsyncFloat64().Gauge("cpu_core_temperature_headroom", instrument.WithDescription("Degrees Celcius a CPU Core can heat up before it reaches its critical temperature"), instrument.WithUnit("C"))
+	if err != nil {
+		return nil, err
+	}
+
+	cpuPower, err := meter.AsyncFloat64().Gauge("cpu_power_watts", instrument.WithDescription("Power drawn by a CPU package, its cores or its memory in Watts"), instrument.WithUnit("W"))
+	if err != nil {
+		return nil, err
+	}
+	cpuEnergy, err := meter.AsyncFloat64().Counter("cpu_energy_joules", instrument.WithDescription("Energy used by a CPU package, its cores or its memory in Joules"), instrument.WithUnit("J"))
+	if err != nil {
+		return nil, err
+	}
+	cpuTDP, err := meter.AsyncFloat64().Gauge("cpu_tdp_watts", instrument.WithDescription("Thermal design power of a CPU package in Watts"), instrument.WithUnit("W"))
+	if err != nil {
+		return nil, err
+	}
+	cpuUnderVoltage, err := meter.AsyncInt64().Gauge("cpu_under_voltage", instrument.WithDescription("1 if the supply voltage of the CPU is too low, otherwise 0"))
+	if err != nil {
+		return nil, err
+	}
+	cpuUnderVoltageOccurred, err := meter.AsyncInt64().Gauge("cpu_under_voltage_occurred", instrument.WithDescription("1 if the supply voltage of the CPU has been too low since boot, otherwise 0"))
+	if err != nil {
+		return nil, err
+	}
+	cpuFrequencyCapped, err := meter.AsyncInt64().Gauge("cpu_frequency_capped", instrument.WithDescription("1 if the firmware caps the frequency of the CPU, otherwise 0"))
+	if err != nil {
+		return nil, err
+	}
+	cpuFrequencyCappedOccurred, err := meter.AsyncInt64().Gauge("cpu_frequency_capped_occurred", instrument.WithDescription("1 if the firmware has capped the frequency of the CPU since boot, otherwise 0"))
+	if err != nil {
+		return nil, err
+	}
+	cpuThrottled, err := meter.AsyncInt64().Gauge("cpu_throttled", instrument.WithDescription("1 if the firmware throttles the CPU, otherwise 0"))
+	if err != nil {
+		return nil, err
+	}
+	cpuThrottledOccurred, err := meter.AsyncInt64().Gauge("cpu_throttled_occurred", instrument.WithDescription("1 if the firmware has throttled the CPU since boot, otherwise 0"))
+	if err != nil {
+		return nil, err
+	}
+	cpuSoftTemperatureLimit, err := meter.AsyncInt64().Gauge("cpu_soft_temperature_limit", instrument.WithDescription("1 if the CPU has reached its soft temperature limit, otherwise 0"))
+	if err != nil {
+		return nil, err
+	}
+	cpuSoftTemperatureLimitOccurred, err := meter.AsyncInt64().Gauge("cpu_soft_temperature_limit_occurred", instrument.WithDescription("1 if the CPU has reached its soft temperature limit since boot, otherwise 0"))
+	if err != nil {
+		return nil, err
+	}
+
+	deviceTemperature, err := meter.AsyncFloat64().Gauge("device_temperature", instrument.WithDescription("Temperature of a device in Celcius"), instrument.WithUnit("C"))
+	if err != nil {
+		return nil, err
+	}
+	deviceTemperatureWarning, err := meter.AsyncFloat64().Gauge("device_temperature_warning", instrument.WithDescription("Temperature above which a device is too hot in Celcius"), instrument.WithUnit("C"))
+	if err != nil {
+		return nil, err
+	}
+	deviceTemperatureCrit, err := meter.AsyncFloat64().Gauge("device_temperature_crit", instrument.WithDescription("Temperature above which a device may be damaged or shut down in Celcius"), instrument.WithUnit("C"))
+	if err != nil {
+		return nil, err
+	}
+	hardwareSensor, err := meter.AsyncFloat64().Gauge("hardware_sensor_value", instrument.WithDescription("Reading of a sensor that is not reported by a more specific metric, the unit depends on the type of sensor"))
+	if err != nil {
+		return nil, err
+	}
+	fanSpeed, err := meter.AsyncFloat64().Gauge("fan_speed_rpm", instrument.WithDescription("Speed of a fan in revolutions per minute"))
+	if err != nil {
+		return nil, err
+	}
+
+	voltage, err := meter.AsyncFloat64().Gauge("voltage_volts", instrument.WithDescription("Voltage of a power rail in Volts"), instrument.WithUnit("V"))
+	if err != nil {
+		return nil, err
+	}
+	thermalZoneTripPoint, err := meter.AsyncFloat64().Gauge("thermal_zone_trip_point_temperature", instrument.WithDescription("Temperature at which a thermal zone takes action, like throttling or shutting down, in Celcius"), instrument.WithUnit("C"))
+	if err != nil {
+		return nil, err
+	}
+	coolingDeviceState, err := meter.AsyncInt64().Gauge("cooling_device_state", instrument.WithDescription("Current state of a cooling device, from 0 (off) to its max state"))
+	if err != nil {
+		return nil, err
+	}
+	coolingDeviceMaxState, err := meter.AsyncInt64().Gauge("cooling_device_max_state", instrument.WithDescription("Highest state of a cooling device"))
+	if err != nil {
+		return nil, err
+	}
+	machineUp, err := meter.AsyncInt64().Gauge("machine_up", instrument.WithDescription("1 if the last read of a machine succeeded, 0 if its driver failed or it has not been read for a few intervals"))
+	if err != nil {
+		return nil, err
+	}
+	machineLastSuccess, err := meter.AsyncFloat64().Gauge("machine_last_success_timestamp_seconds", instrument.WithDescription("Unix time of the last successful read of a machine"), instrument.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	pollErrors, err := meter.SyncInt64().Counter("poll_errors", instrument.WithDescription("Number of times the sensors could not be read, by kind of error (timeout, driver)"))
+	if err != nil {
+		return nil, err
+	}
 
 	sink := &metricsSink{
-		CPUCoreTemperature: cpuCoreTemperature,
-		CPUCoreLoad:        cpuCoreLoad,
-		CPUInfoPollCount:   cpuInfoPollCount,
-		CPUFrequency:       cpuFrequency,
-		CPUFSBFrequency:    cpuFSBFrequency,
+		CPUCoreTemperature:              cpuCoreTemperature,
+		CPUPackageTemperature:           cpuPackageTemperature,
+		CPUCoreLoad:                     cpuCoreLoad,
+		CPUInfoPollCount:                cpuInfoPollCount,
+		CPUFrequency:                    cpuFrequency,
+		CPUFSBFrequency:                 cpuFSBFrequency,
+		CPUCoreFrequency:                cpuCoreFrequency,
+		CPUCoreFrequencyMin:             cpuCoreFrequencyMin,
+		CPUCoreFrequencyMax:             cpuCoreFrequencyMax,
+		CPUCoreTemperatureMax:           cpuCoreTemperatureMax,
+		CPUCoreTemperatureCrit:          cpuCoreTemperatureCrit,
+		CPUCoreTemperatureAlarm:         cpuCoreTemperatureAlarm,
+		CPUCoreTemperatureHeadroom:      cpuCoreTemperatureHeadroom,
+		CPUPower:                        cpuPower,
+		CPUEnergy:                       cpuEnergy,
+		CPUTDP:                          cpuTDP,
+		CPUUnderVoltage:                 cpuUnderVoltage,
+		CPUUnderVoltageOccurred:         cpuUnderVoltageOccurred,
+		CPUFrequencyCapped:              cpuFrequencyCapped,
+		CPUFrequencyCappedOccurred:      cpuFrequencyCappedOccurred,
+		CPUThrottled:                    cpuThrottled,
+		CPUThrottledOccurred:            cpuThrottledOccurred,
+		CPUSoftTemperatureLimit:         cpuSoftTemperatureLimit,
+		CPUSoftTemperatureLimitOccurred: cpuSoftTemperatureLimitOccurred,
+		DeviceTemperature:               deviceTemperature,
+		DeviceTemperatureWarning:        deviceTemperatureWarning,
+		DeviceTemperatureCrit:           deviceTemperatureCrit,
+		HardwareSensor:                  hardwareSensor,
+		FanSpeed:                        fanSpeed,
+		Voltage:                         voltage,
+		ThermalZoneTripPoint:            thermalZoneTripPoint,
+		CoolingDeviceState:              coolingDeviceState,
+		CoolingDeviceMaxState:           coolingDeviceMaxState,
+		MachineUp:                       machineUp,
+		MachineLastSuccess:              machineLastSuccess,
+		PollErrors:                      pollErrors,
+		now:                             time.Now,
+		lastValue:                       map[string]*machineState{},
 	}
 
-	meter.RegisterCallback([]instrument.Asynchronous{cpuCoreTemperature, cpuCoreLoad, cpuFrequency, cpuFSBFrequency}, func(ctx context.Context) {
+	meter.RegisterCallback([]instrument.Asynchronous{cpuCoreTemperature, cpuPackageTemperature, cpuCoreLoad, cpuFrequency, cpuFSBFrequency, cpuCoreFrequency, cpuCoreFrequencyMin, cpuCoreFrequencyMax, cpuCoreTemperatureMax, cpuCoreTemperatureCrit, cpuCoreTemperatureAlarm, cpuCoreTemperatureHeadroom, cpuPower, cpuEnergy, cpuTDP, cpuUnderVoltage, cpuUnderVoltageOccurred, cpuFrequencyCapped, cpuFrequencyCappedOccurred, cpuThrottled, cpuThrottledOccurred, cpuSoftTemperatureLimit, cpuSoftTemperatureLimitOccurred, deviceTemperature, deviceTemperatureWarning, deviceTemperatureCrit, hardwareSensor, fanSpeed, voltage, thermalZoneTripPoint, coolingDeviceState, coolingDeviceMaxState, machineUp, machineLastSuccess}, func(ctx context.Context) {
 		sink.ObserveAsync(ctx)
 	})
 
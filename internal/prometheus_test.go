@@ -0,0 +1,311 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+var (
+	otelScopeRegexp = regexp.MustCompile(`,otel_scope_name="[^"]*",otel_scope_version="[^"]*"`)
+)
+
+func TestMetricsSink(t *testing.T) {
+	ctx := context.Background()
+	sink, handler, err := newMetricsSink(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.Observe(ctx, &pb.MachineMetrics{
+		Name: "machine-name",
+		Device: []*pb.DeviceMetrics{{
+			Name:        "some-processor",
+			Kind:        "cpu",
+			Temperature: 45,
+			Cpu: &pb.CpuDeviceMetrics{
+				Load:                []int32{1, 2},
+				Temperature:         []float64{50, 40},
+				PackageTemperature:  52,
+				NumCores:            2,
+				FrequencyMhz:        1000,
+				FsbFrequencyMhz:     100,
+				CoreFrequencyHz:     []float64{1200000000, 3400000000},
+				CoreFrequencyMinHz:  []float64{800000000, 800000000},
+				CoreFrequencyMaxHz:  []float64{3400000000, 3400000000},
+				TemperatureMax:      []float64{90, 90},
+				TemperatureCrit:     []float64{100, 100},
+				TemperatureAlarm:    []bool{false, true},
+				PackagePowerWatts:   35.5,
+				PackageEnergyJoules: 1200,
+				DramPowerWatts:      2.5,
+				TdpWatts:            65,
+			},
+		}, {
+			Name:        "some-processor",
+			Kind:        "cpu",
+			Temperature: 60,
+			Cpu: &pb.CpuDeviceMetrics{
+				Temperature: []float64{60},
+				NumCores:    1,
+				Socket:      1,
+			},
+			Throttled: &pb.ThrottledMetrics{
+				Flags:                0x50005,
+				UnderVoltage:         true,
+				Throttled:            true,
+				UnderVoltageOccurred: true,
+				ThrottledOccurred:    true,
+			},
+		}, {
+			Name: "nct6775-isa-0290",
+			Kind: "fan",
+			Fan: &pb.FanDeviceMetrics{
+				Label: "CPU Fan",
+				Rpm:   1146,
+			},
+		}, {
+			Name: "nct6775-isa-0290",
+			Kind: "voltage",
+			Voltage: &pb.VoltageDeviceMetrics{
+				Label: "+12V",
+				Volts: 12.096,
+			},
+		}, {
+			Name:        "Samsung SSD 970 EVO Plus 1TB",
+			Kind:        "storage",
+			Temperature: 44.85,
+			Storage: &pb.StorageDeviceMetrics{
+				Device:             "nvme0",
+				TemperatureWarning: 81.85,
+				TemperatureCrit:    84.85,
+			},
+		}, {
+			Name: "jc42-i2c-0-18",
+			Kind: "sensor",
+			Chip: "jc42-i2c-0-18",
+			Sensor: []*pb.SensorReading{
+				{Label: "temp1", Type: "temp", Unit: "C", Value: 36.5},
+				{Label: "PMBus Power", Type: "power", Unit: "W", Value: 120},
+			},
+		}, {
+			Name:        "cpu-thermal",
+			Kind:        "thermal_zone",
+			Chip:        "thermal_zone0",
+			Temperature: 62.3,
+			ThermalZone: &pb.ThermalZoneMetrics{
+				Zone:   "thermal_zone0",
+				Type:   "cpu-thermal",
+				Policy: "step_wise",
+				TripPoint: []*pb.TripPoint{
+					{Type: "passive", Temperature: 75, Hysteresis: 2},
+					{Type: "critical", Temperature: 90, Index: 2},
+				},
+			},
+		}, {
+			Name:          "pwm-fan",
+			Kind:          "cooling_device",
+			Chip:          "cooling_device0",
+			CoolingDevice: &pb.CoolingDeviceMetrics{Device: "cooling_device0", Type: "pwm-fan", CurState: 2, MaxState: 4},
+		}},
+	})
+
+	sink.Observe(ctx, &pb.MachineMetrics{
+		Name: "gaming-pc",
+		Device: []*pb.DeviceMetrics{{
+			Name:        "Intel(R) Core(TM) i7-8700K CPU @ 3.70GHz",
+			Kind:        "cpu",
+			Temperature: 61,
+			Cpu: &pb.CpuDeviceMetrics{
+				Temperature: []float64{61},
+				NumCores:    1,
+			},
+		}},
+	})
+	sink.ObserveError(ctx, errorKindTimeout)
+	sink.ObserveError(ctx, errorKindTimeout)
+	sink.ObserveError(ctx, errorKindDriver)
+
+	got := scrape(t, handler)
+	for _, want := range []string{
+		`cpu_core_temperature{core="1",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 40`,
+		`cpu_core_temperature{core="0",hostname="machine-name",kind="cpu",name="some-processor",socket="1"} 60`,
+		`cpu_package_temperature{hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 52`,
+		`cpu_core_load{core="0",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 1`,
+		`cpu_frequency{core_count="2",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 1e+09`,
+		`cpu_core_frequency_hertz{core="1",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 3.4e+09`,
+		`cpu_core_frequency_min_hertz{core="0",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 8e+08`,
+		`cpu_core_frequency_max_hertz{core="0",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 3.4e+09`,
+		`cpu_core_temperature_max{core="0",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 90`,
+		`cpu_core_temperature_crit{core="1",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 100`,
+		`cpu_core_temperature_alarm{core="1",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 1`,
+		`cpu_core_temperature_headroom{core="0",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 50`,
+		`cpu_core_temperature_headroom{core="1",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 60`,
+		`cpu_power_watts{domain="package",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 35.5`,
+		`cpu_power_watts{domain="dram",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 2.5`,
+		`cpu_energy_joules_total{domain="package",hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 1200`,
+		`cpu_tdp_watts{hostname="machine-name",kind="cpu",name="some-processor",socket="0"} 65`,
+		`cpu_under_voltage{hostname="machine-name",kind="cpu",name="some-processor",socket="1"} 1`,
+		`cpu_under_voltage_occurred{hostname="machine-name",kind="cpu",name="some-processor",socket="1"} 1`,
+		`cpu_frequency_capped{hostname="machine-name",kind="cpu",name="some-processor",socket="1"} 0`,
+		`cpu_frequency_capped_occurred{hostname="machine-name",kind="cpu",name="some-processor",socket="1"} 0`,
+		`cpu_throttled{hostname="machine-name",kind="cpu",name="some-processor",socket="1"} 1`,
+		`cpu_throttled_occurred{hostname="machine-name",kind="cpu",name="some-processor",socket="1"} 1`,
+		`cpu_soft_temperature_limit{hostname="machine-name",kind="cpu",name="some-processor",socket="1"} 0`,
+		`cpu_soft_temperature_limit_occurred{hostname="machine-name",kind="cpu",name="some-processor",socket="1"} 0`,
+		`device_temperature{device="nvme0",hostname="machine-name",kind="storage",name="Samsung SSD 970 EVO Plus 1TB"} 44.85`,
+		`device_temperature_warning{device="nvme0",hostname="machine-name",kind="storage",name="Samsung SSD 970 EVO Plus 1TB"} 81.85`,
+		`device_temperature_crit{device="nvme0",hostname="machine-name",kind="storage",name="Samsung SSD 970 EVO Plus 1TB"} 84.85`,
+		`hardware_sensor_value{chip="jc42-i2c-0-18",hostname="machine-name",kind="sensor",label="temp1",name="jc42-i2c-0-18",type="temp"} 36.5`,
+		`hardware_sensor_value{chip="jc42-i2c-0-18",hostname="machine-name",kind="sensor",label="PMBus Power",name="jc42-i2c-0-18",type="power"} 120`,
+		`fan_speed_rpm{hostname="machine-name",kind="fan",label="CPU Fan",name="nct6775-isa-0290"} 1146`,
+		`voltage_volts{hostname="machine-name",kind="voltage",label="+12V",name="nct6775-isa-0290"} 12.096`,
+		`device_temperature{hostname="machine-name",kind="thermal_zone",name="cpu-thermal",zone="thermal_zone0"} 62.3`,
+		`thermal_zone_trip_point_temperature{hostname="machine-name",kind="thermal_zone",name="cpu-thermal",trip="0",type="passive",zone="thermal_zone0"} 75`,
+		`thermal_zone_trip_point_temperature{hostname="machine-name",kind="thermal_zone",name="cpu-thermal",trip="2",type="critical",zone="thermal_zone0"} 90`,
+		`cooling_device_state{device="cooling_device0",hostname="machine-name",kind="cooling_device",name="pwm-fan"} 2`,
+		`cooling_device_max_state{device="cooling_device0",hostname="machine-name",kind="cooling_device",name="pwm-fan"} 4`,
+		`cpu_core_temperature{core="0",hostname="gaming-pc",kind="cpu",name="Intel(R) Core(TM) i7-8700K CPU @ 3.70GHz",socket="0"} 61`,
+		`machine_up{hostname="machine-name"} 1`,
+		`machine_up{hostname="gaming-pc"} 1`,
+		`poll_errors_total{kind="timeout"} 2`,
+		`poll_errors_total{kind="driver"} 1`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("metrics do not contain '%s'\n%s", want, got)
+		}
+	}
+	for _, notWant := range []string{
+		// The package temperature of socket 1 and of gaming-pc is not known.
+		`cpu_package_temperature{hostname="machine-name",kind="cpu",name="some-processor",socket="1"}`,
+		`cpu_package_temperature{hostname="gaming-pc"`,
+	} {
+		if strings.Contains(got, notWant) {
+			t.Errorf("metrics contain '%s'\n%s", notWant, got)
+		}
+	}
+}
+
+// sequenceDriver returns the next of its results on every read.
+type sequenceDriver struct {
+	results []*fakeDriver
+}
+
+func (d *sequenceDriver) Get() (*pb.MachineMetrics, error) {
+	next := d.results[0]
+	d.results = d.results[1:]
+	return next.Get()
+}
+
+func TestMetricsSinkMachineDown(t *testing.T) {
+	ctx := context.Background()
+	sink, handler, err := newMetricsSink(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.now = func() time.Time { return time.Unix(1700000000, 0) }
+	gamingPC := &pb.MachineMetrics{
+		Name: "gaming-pc",
+		Device: []*pb.DeviceMetrics{{
+			Name: "Intel(R) Core(TM) i7-8700K CPU @ 3.70GHz",
+			Kind: "cpu",
+			Cpu:  &pb.CpuDeviceMetrics{Temperature: []float64{61}, NumCores: 1},
+		}},
+	}
+	ds := []common.ContextDriver{
+		common.WithContext(&sequenceDriver{results: []*fakeDriver{{mm: gamingPC}, {err: errors.New("connection refused")}, {mm: gamingPC}}}),
+		common.WithContext(&fakeDriver{mm: &pb.MachineMetrics{Name: "build-pc"}}),
+	}
+	names := make([]string, len(ds))
+	temperature := `cpu_core_temperature{core="0",hostname="gaming-pc",kind="cpu",name="Intel(R) Core(TM) i7-8700K CPU @ 3.70GHz",socket="0"} 61`
+
+	poll(ctx, ds, names, time.Second, newMultiSink(sink))
+	got := scrape(t, handler)
+	for _, want := range []string{temperature, `machine_up{hostname="gaming-pc"} 1`, `machine_last_success_timestamp_seconds{hostname="gaming-pc"} 1.7e+09`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("first poll does not contain '%s'\n%s", want, got)
+		}
+	}
+
+	// The second poll of gaming-pc fails, its last reading is no longer exported.
+	sink.now = func() time.Time { return time.Unix(1700000001, 0) }
+	poll(ctx, ds, names, time.Second, newMultiSink(sink))
+	got = scrape(t, handler)
+	for _, want := range []string{`machine_up{hostname="gaming-pc"} 0`, `machine_last_success_timestamp_seconds{hostname="gaming-pc"} 1.7e+09`, `machine_up{hostname="build-pc"} 1`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("second poll does not contain '%s'\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `cpu_core_temperature{core="0",hostname="gaming-pc"`) {
+		t.Errorf("second poll still exports the temperature of gaming-pc\n%s", got)
+	}
+
+	poll(ctx, ds, names, time.Second, newMultiSink(sink))
+	got = scrape(t, handler)
+	for _, want := range []string{temperature, `machine_up{hostname="gaming-pc"} 1`, `machine_last_success_timestamp_seconds{hostname="gaming-pc"} 1.700000001e+09`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("third poll does not contain '%s'\n%s", want, got)
+		}
+	}
+}
+
+func TestMetricsSinkStale(t *testing.T) {
+	ctx := context.Background()
+	sink, handler, err := newMetricsSink(ctx, 3*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	sink.now = func() time.Time { return now }
+	sink.Observe(ctx, &pb.MachineMetrics{
+		Name:   "gaming-pc",
+		Device: []*pb.DeviceMetrics{{Name: "Samsung SSD 970 EVO Plus 1TB", Kind: "storage", Temperature: 44.85}},
+	})
+	temperature := `device_temperature{hostname="gaming-pc",kind="storage",name="Samsung SSD 970 EVO Plus 1TB"} 44.85`
+
+	now = now.Add(3 * time.Second)
+	if got := scrape(t, handler); !strings.Contains(got, temperature) || !strings.Contains(got, `machine_up{hostname="gaming-pc"} 1`) {
+		t.Errorf("a reading that is 3s old should be exported\n%s", got)
+	}
+
+	// A remote that hangs forever is never marked down by its driver.
+	now = now.Add(time.Second)
+	got := scrape(t, handler)
+	if strings.Contains(got, temperature) || !strings.Contains(got, `machine_up{hostname="gaming-pc"} 0`) {
+		t.Errorf("a reading that is 4s old should be down\n%s", got)
+	}
+}
+
+func scrape(t *testing.T, handler http.Handler) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Result().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The OpenTelemetry scope is the same on every metric so it is removed to keep the expectations short.
+	return otelScopeRegexp.ReplaceAllString(string(body), "")
+}
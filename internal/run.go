@@ -19,17 +19,25 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
-	"github.com/jeremyje/coretemp-exporter/drivers"
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	"github.com/jeremyje/coretemp-exporter/drivers/relabel"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
 	"github.com/jeremyje/gomain"
 )
 
+// staleIntervals is how many polling intervals a machine can go without a reading before it is reported down.
+const staleIntervals = 3
+
 type Args struct {
 	Endpoint              string
 	Interval              time.Duration
 	Log                   string
 	Console               bool
+	Config                string
+	Driver                string
 	ServiceControlCommand string
 }
 
@@ -49,13 +57,24 @@ func run(args *Args, wait func()) error {
 	ctx := context.Background()
 	handler = http.NewServeMux()
 
+	cfg, err := loadConfig(args.Config)
+	if err != nil {
+		return err
+	}
+
+	localDrivers, err := newDrivers(args)
+	if err != nil {
+		return err
+	}
+
+	var probe *probeHandler
 	if args.Endpoint != "" {
-		metrics, promHandler, err := newMetricsSink(ctx)
+		metrics, promHandler, err := newMetricsSink(ctx, staleIntervals*args.Interval)
 		if err != nil {
 			return err
 		}
 		sinks = append(sinks, metrics)
-		handler = promHandler
+		handler, probe = newServeMux(cfg, args.Interval, promHandler)
 	}
 
 	if args.Console {
@@ -78,18 +97,17 @@ func run(args *Args, wait func()) error {
 	go func() {
 		ctx := context.Background()
 
-		d := drivers.New()
+		ds := []common.ContextDriver{}
+		for _, d := range localDrivers {
+			ds = append(ds, common.WithContext(relabel.New(d, cfg.Sensors)))
+		}
+		names := make([]string, len(ds))
 		for {
 			select {
 			case <-done:
 				return
 			case <-ticker.C:
-				info, err := d.Get()
-				if err != nil {
-					log.Printf("ERROR: %s", err)
-				}
-
-				ms.Observe(ctx, info)
+				poll(ctx, ds, names, args.Interval, ms)
 			}
 		}
 	}()
@@ -108,12 +126,76 @@ func run(args *Args, wait func()) error {
 
 	go func() {
 		wait()
-		ctx := context.Background()
-		s.Shutdown(ctx)
 		ticker.Stop()
 		done <- true
 		close(done)
+		// The drivers are closed once the last poll is over and before Serve returns, so remote connections are not left open.
+		closeDrivers(localDrivers)
+		if probe != nil {
+			probe.Close()
+		}
+		ctx := context.Background()
+		s.Shutdown(ctx)
 	}()
 
 	return s.Serve(lis)
 }
+
+// poll reads the sensors of every driver once, at the same time, and passes the results to the sinks one after the other.
+// The deadline is the polling interval so a driver that hangs cannot hold up the next poll.
+// names holds the machine each driver read last, the sinks are told that machine is down when its driver fails.
+func poll(ctx context.Context, ds []common.ContextDriver, names []string, timeout time.Duration, ms *multiSink) {
+	pollCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	infos := make([]*pb.MachineMetrics, len(ds))
+	errs := make([]error, len(ds))
+	var wg sync.WaitGroup
+	for i, d := range ds {
+		wg.Add(1)
+		go func(i int, d common.ContextDriver) {
+			defer wg.Done()
+			infos[i], errs[i] = d.GetContext(pollCtx)
+		}(i, d)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			if common.IsTimeout(err) {
+				log.Printf("TIMEOUT: the sensors were not read within %s: %s", timeout, err)
+				ms.ObserveError(ctx, errorKindTimeout)
+			} else {
+				log.Printf("ERROR: %s", err)
+				ms.ObserveError(ctx, errorKindDriver)
+			}
+			if names[i] != "" {
+				ms.ObserveDown(ctx, names[i])
+			}
+		} else if infos[i] != nil {
+			names[i] = infos[i].GetName()
+		}
+		ms.Observe(ctx, infos[i])
+	}
+}
+
+// closeDrivers releases the connections and other resources held by the drivers.
+func closeDrivers(ds []common.Driver) {
+	for _, d := range ds {
+		if err := common.Close(d); err != nil {
+			log.Printf("ERROR: cannot close driver, err= %s", err)
+		}
+	}
+}
+
+// newServeMux serves the metrics and, if the config has probe modules, /probe. The probe handler is nil without probe modules.
+func newServeMux(cfg *Config, interval time.Duration, promHandler http.Handler) (*http.ServeMux, *probeHandler) {
+	mux := http.NewServeMux()
+	var probe *probeHandler
+	if len(cfg.Probe.Modules) > 0 {
+		probe = newProbeHandler(cfg.Probe, cfg.Sensors, interval)
+		mux.Handle("/probe", probe)
+	}
+	mux.Handle("/", promHandler)
+	return mux, probe
+}
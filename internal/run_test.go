@@ -0,0 +1,128 @@
+// Copyright 2023 Jeremy Edwards
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jeremyje/coretemp-exporter/drivers/common"
+	pb "github.com/jeremyje/coretemp-exporter/proto"
+)
+
+type recordingSink struct {
+	observed []*pb.MachineMetrics
+	errors   []string
+	down     []string
+}
+
+func (s *recordingSink) Observe(ctx context.Context, info *pb.MachineMetrics) {
+	s.observed = append(s.observed, info)
+}
+
+func (s *recordingSink) ObserveError(ctx context.Context, kind string) {
+	s.errors = append(s.errors, kind)
+}
+
+func (s *recordingSink) ObserveDown(ctx context.Context, name string) {
+	s.down = append(s.down, name)
+}
+
+type fakeDriver struct {
+	mm      *pb.MachineMetrics
+	err     error
+	release chan struct{}
+}
+
+func (d *fakeDriver) Get() (*pb.MachineMetrics, error) {
+	if d.release != nil {
+		<-d.release
+	}
+	return d.mm, d.err
+}
+
+func TestPollMany(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	sink := &recordingSink{}
+	poll(context.Background(), []common.ContextDriver{
+		common.WithContext(&fakeDriver{mm: &pb.MachineMetrics{Name: "a"}}),
+		common.WithContext(&fakeDriver{mm: &pb.MachineMetrics{Name: "hung"}, release: release}),
+		common.WithContext(&fakeDriver{mm: &pb.MachineMetrics{Name: "b"}}),
+	}, []string{"", "hung", ""}, 10*time.Millisecond, newMultiSink(sink))
+
+	got := []string{}
+	for _, mm := range sink.observed {
+		got = append(got, mm.GetName())
+	}
+	// The hung driver does not hold up the others.
+	if diff := cmp.Diff([]string{"a", "", "b"}, got); diff != "" {
+		t.Errorf("observed mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{errorKindTimeout}, sink.errors); diff != "" {
+		t.Errorf("errors mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"hung"}, sink.down); diff != "" {
+		t.Errorf("down mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPoll(t *testing.T) {
+	tests := []struct {
+		name       string
+		driver     common.Driver
+		wantErrors []string
+		wantName   string
+	}{
+		{
+			name:       "ok",
+			driver:     &fakeDriver{mm: &pb.MachineMetrics{Name: "ok"}},
+			wantErrors: nil,
+			wantName:   "ok",
+		},
+		{
+			name:       "error",
+			driver:     &fakeDriver{err: errors.New("broken")},
+			wantErrors: []string{errorKindDriver},
+		},
+		{
+			name:       "timeout",
+			driver:     &fakeDriver{mm: &pb.MachineMetrics{Name: "late"}, release: make(chan struct{})},
+			wantErrors: []string{errorKindTimeout},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if d := tc.driver.(*fakeDriver); d.release != nil {
+				t.Cleanup(func() { close(d.release) })
+			}
+			sink := &recordingSink{}
+			poll(context.Background(), []common.ContextDriver{common.WithContext(tc.driver)}, make([]string, 1), 10*time.Millisecond, newMultiSink(sink))
+			if diff := cmp.Diff(tc.wantErrors, sink.errors); diff != "" {
+				t.Errorf("errors mismatch (-want +got):\n%s", diff)
+			}
+			if len(sink.observed) != 1 || sink.observed[0].GetName() != tc.wantName {
+				t.Errorf("observed %v, want one record named '%s'", sink.observed, tc.wantName)
+			}
+		})
+	}
+}
@@ -24,6 +24,20 @@ type HardwareDataSink interface {
 	Observe(ctx context.Context, info *pb.MachineMetrics)
 }
 
+const (
+	// errorKindTimeout is a poll that did not finish before its deadline.
+	errorKindTimeout = "timeout"
+	// errorKindDriver is any other error returned by a driver.
+	errorKindDriver = "driver"
+)
+
+// ErrorSink is implemented by the sinks that count the errors of the drivers.
+type ErrorSink interface {
+	ObserveError(ctx context.Context, kind string)
+	// ObserveDown is called with the name of the machine a driver read last when the driver fails.
+	ObserveDown(ctx context.Context, name string)
+}
+
 type multiSink struct {
 	sinks []HardwareDataSink
 }
@@ -33,6 +47,23 @@ func (m *multiSink) Observe(ctx context.Context, info *pb.MachineMetrics) {
 		s.Observe(ctx, info)
 	}
 }
+
+func (m *multiSink) ObserveError(ctx context.Context, kind string) {
+	for _, s := range m.sinks {
+		if es, ok := s.(ErrorSink); ok {
+			es.ObserveError(ctx, kind)
+		}
+	}
+}
+
+func (m *multiSink) ObserveDown(ctx context.Context, name string) {
+	for _, s := range m.sinks {
+		if es, ok := s.(ErrorSink); ok {
+			es.ObserveDown(ctx, name)
+		}
+	}
+}
+
 func newMultiSink(s ...HardwareDataSink) *multiSink {
 	return &multiSink{
 		sinks: s,
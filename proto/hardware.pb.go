@@ -47,10 +47,54 @@ type CpuDeviceMetrics struct {
 	Temperature []float64 `protobuf:"fixed64,2,rep,packed,name=temperature,proto3" json:"temperature,omitempty"`
 	// NumCores is the total number of cores across all CPUs on the machine.
 	NumCores int32 `protobuf:"varint,3,opt,name=num_cores,json=numCores,proto3" json:"num_cores,omitempty"`
-	// Frequency is the clock frequency (Hz) of the CPU.
+	// Frequency is the clock frequency (MHz) of the CPU.
 	FrequencyMhz float64 `protobuf:"fixed64,4,opt,name=frequency_mhz,json=frequencyMhz,proto3" json:"frequency_mhz,omitempty"`
 	// FSBFrequency is the clock frequency of the front side bus.
 	FsbFrequencyMhz float64 `protobuf:"fixed64,5,opt,name=fsb_frequency_mhz,json=fsbFrequencyMhz,proto3" json:"fsb_frequency_mhz,omitempty"`
+	// LoadUser is the percentage [0-100] of time each core spent running user code.
+	LoadUser []float64 `protobuf:"fixed64,6,rep,packed,name=load_user,json=loadUser,proto3" json:"load_user,omitempty"`
+	// LoadSystem is the percentage [0-100] of time each core spent in the kernel, including interrupts.
+	LoadSystem []float64 `protobuf:"fixed64,7,rep,packed,name=load_system,json=loadSystem,proto3" json:"load_system,omitempty"`
+	// LoadIowait is the percentage [0-100] of time each core was idle while waiting for I/O.
+	LoadIowait []float64 `protobuf:"fixed64,8,rep,packed,name=load_iowait,json=loadIowait,proto3" json:"load_iowait,omitempty"`
+	// LoadSteal is the percentage [0-100] of time each core was taken by the hypervisor for other guests.
+	LoadSteal []float64 `protobuf:"fixed64,9,rep,packed,name=load_steal,json=loadSteal,proto3" json:"load_steal,omitempty"`
+	// CoreFrequencyHz is the current clock frequency (Hz) of each logical CPU.
+	CoreFrequencyHz []float64 `protobuf:"fixed64,10,rep,packed,name=core_frequency_hz,json=coreFrequencyHz,proto3" json:"core_frequency_hz,omitempty"`
+	// CoreFrequencyMinHz is the lowest clock frequency (Hz) that each logical CPU is allowed to run at.
+	CoreFrequencyMinHz []float64 `protobuf:"fixed64,11,rep,packed,name=core_frequency_min_hz,json=coreFrequencyMinHz,proto3" json:"core_frequency_min_hz,omitempty"`
+	// CoreFrequencyMaxHz is the highest clock frequency (Hz) that each logical CPU is allowed to run at.
+	CoreFrequencyMaxHz []float64 `protobuf:"fixed64,12,rep,packed,name=core_frequency_max_hz,json=coreFrequencyMaxHz,proto3" json:"core_frequency_max_hz,omitempty"`
+	// Socket is the physical id of the CPU package on machines with more than one CPU.
+	Socket int32 `protobuf:"varint,13,opt,name=socket,proto3" json:"socket,omitempty"`
+	// PackageTemperature is the temperature of the whole CPU package in celcius, this is the temperature the CPU throttles on.
+	PackageTemperature float64 `protobuf:"fixed64,14,opt,name=package_temperature,json=packageTemperature,proto3" json:"package_temperature,omitempty"`
+	// CcdTemperature is the temperature of each core complex die (CCD) in celcius, only reported by AMD CPUs.
+	CcdTemperature []float64 `protobuf:"fixed64,15,rep,packed,name=ccd_temperature,json=ccdTemperature,proto3" json:"ccd_temperature,omitempty"`
+	// TemperatureMax is the high temperature threshold of each core in celcius.
+	TemperatureMax []float64 `protobuf:"fixed64,16,rep,packed,name=temperature_max,json=temperatureMax,proto3" json:"temperature_max,omitempty"`
+	// TemperatureCrit is the critical temperature of each core in celcius, for Intel CPUs this is TjMax.
+	TemperatureCrit []float64 `protobuf:"fixed64,17,rep,packed,name=temperature_crit,json=temperatureCrit,proto3" json:"temperature_crit,omitempty"`
+	// TemperatureAlarm is true for each core that has reached one of its temperature thresholds.
+	TemperatureAlarm []bool `protobuf:"varint,18,rep,packed,name=temperature_alarm,json=temperatureAlarm,proto3" json:"temperature_alarm,omitempty"`
+	// PackagePowerWatts is the power drawn by the whole CPU package in watts.
+	PackagePowerWatts float64 `protobuf:"fixed64,19,opt,name=package_power_watts,json=packagePowerWatts,proto3" json:"package_power_watts,omitempty"`
+	// CorePowerWatts is the power drawn by the cores of the CPU package in watts.
+	CorePowerWatts float64 `protobuf:"fixed64,20,opt,name=core_power_watts,json=corePowerWatts,proto3" json:"core_power_watts,omitempty"`
+	// DramPowerWatts is the power drawn by the memory attached to the CPU package in watts.
+	DramPowerWatts float64 `protobuf:"fixed64,21,opt,name=dram_power_watts,json=dramPowerWatts,proto3" json:"dram_power_watts,omitempty"`
+	// PackageEnergyJoules is the energy used by the whole CPU package since the exporter started in joules.
+	PackageEnergyJoules float64 `protobuf:"fixed64,22,opt,name=package_energy_joules,json=packageEnergyJoules,proto3" json:"package_energy_joules,omitempty"`
+	// CoreEnergyJoules is the energy used by the cores of the CPU package since the exporter started in joules.
+	CoreEnergyJoules float64 `protobuf:"fixed64,23,opt,name=core_energy_joules,json=coreEnergyJoules,proto3" json:"core_energy_joules,omitempty"`
+	// DramEnergyJoules is the energy used by the memory attached to the CPU package since the exporter started in joules.
+	DramEnergyJoules float64 `protobuf:"fixed64,24,opt,name=dram_energy_joules,json=dramEnergyJoules,proto3" json:"dram_energy_joules,omitempty"`
+	// TdpWatts is the thermal design power of the CPU package in watts.
+	TdpWatts float64 `protobuf:"fixed64,25,opt,name=tdp_watts,json=tdpWatts,proto3" json:"tdp_watts,omitempty"`
+	// Multiplier is the clock multiplier of the CPU package, the frequency divided by the FSB frequency.
+	Multiplier float64 `protobuf:"fixed64,26,opt,name=multiplier,proto3" json:"multiplier,omitempty"`
+	// CoreMultiplier is the clock multiplier of each core.
+	CoreMultiplier []float64 `protobuf:"fixed64,27,rep,packed,name=core_multiplier,json=coreMultiplier,proto3" json:"core_multiplier,omitempty"`
 }
 
 func (x *CpuDeviceMetrics) Reset() {
@@ -120,6 +164,877 @@ func (x *CpuDeviceMetrics) GetFsbFrequencyMhz() float64 {
 	return 0
 }
 
+func (x *CpuDeviceMetrics) GetLoadUser() []float64 {
+	if x != nil {
+		return x.LoadUser
+	}
+	return nil
+}
+
+func (x *CpuDeviceMetrics) GetLoadSystem() []float64 {
+	if x != nil {
+		return x.LoadSystem
+	}
+	return nil
+}
+
+func (x *CpuDeviceMetrics) GetLoadIowait() []float64 {
+	if x != nil {
+		return x.LoadIowait
+	}
+	return nil
+}
+
+func (x *CpuDeviceMetrics) GetLoadSteal() []float64 {
+	if x != nil {
+		return x.LoadSteal
+	}
+	return nil
+}
+
+func (x *CpuDeviceMetrics) GetCoreFrequencyHz() []float64 {
+	if x != nil {
+		return x.CoreFrequencyHz
+	}
+	return nil
+}
+
+func (x *CpuDeviceMetrics) GetCoreFrequencyMinHz() []float64 {
+	if x != nil {
+		return x.CoreFrequencyMinHz
+	}
+	return nil
+}
+
+func (x *CpuDeviceMetrics) GetCoreFrequencyMaxHz() []float64 {
+	if x != nil {
+		return x.CoreFrequencyMaxHz
+	}
+	return nil
+}
+
+func (x *CpuDeviceMetrics) GetSocket() int32 {
+	if x != nil {
+		return x.Socket
+	}
+	return 0
+}
+
+func (x *CpuDeviceMetrics) GetPackageTemperature() float64 {
+	if x != nil {
+		return x.PackageTemperature
+	}
+	return 0
+}
+
+func (x *CpuDeviceMetrics) GetCcdTemperature() []float64 {
+	if x != nil {
+		return x.CcdTemperature
+	}
+	return nil
+}
+
+func (x *CpuDeviceMetrics) GetTemperatureMax() []float64 {
+	if x != nil {
+		return x.TemperatureMax
+	}
+	return nil
+}
+
+func (x *CpuDeviceMetrics) GetTemperatureCrit() []float64 {
+	if x != nil {
+		return x.TemperatureCrit
+	}
+	return nil
+}
+
+func (x *CpuDeviceMetrics) GetTemperatureAlarm() []bool {
+	if x != nil {
+		return x.TemperatureAlarm
+	}
+	return nil
+}
+
+func (x *CpuDeviceMetrics) GetPackagePowerWatts() float64 {
+	if x != nil {
+		return x.PackagePowerWatts
+	}
+	return 0
+}
+
+func (x *CpuDeviceMetrics) GetCorePowerWatts() float64 {
+	if x != nil {
+		return x.CorePowerWatts
+	}
+	return 0
+}
+
+func (x *CpuDeviceMetrics) GetDramPowerWatts() float64 {
+	if x != nil {
+		return x.DramPowerWatts
+	}
+	return 0
+}
+
+func (x *CpuDeviceMetrics) GetPackageEnergyJoules() float64 {
+	if x != nil {
+		return x.PackageEnergyJoules
+	}
+	return 0
+}
+
+func (x *CpuDeviceMetrics) GetCoreEnergyJoules() float64 {
+	if x != nil {
+		return x.CoreEnergyJoules
+	}
+	return 0
+}
+
+func (x *CpuDeviceMetrics) GetDramEnergyJoules() float64 {
+	if x != nil {
+		return x.DramEnergyJoules
+	}
+	return 0
+}
+
+func (x *CpuDeviceMetrics) GetTdpWatts() float64 {
+	if x != nil {
+		return x.TdpWatts
+	}
+	return 0
+}
+
+func (x *CpuDeviceMetrics) GetMultiplier() float64 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+func (x *CpuDeviceMetrics) GetCoreMultiplier() []float64 {
+	if x != nil {
+		return x.CoreMultiplier
+	}
+	return nil
+}
+
+// FanDeviceMetrics holds the speed and health of a fan.
+type FanDeviceMetrics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Label of the fan, for example "CPU Fan" or "fan1" if the fan is not labeled.
+	Label string `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	// Rpm is the current speed of the fan in revolutions per minute.
+	Rpm float64 `protobuf:"fixed64,2,opt,name=rpm,proto3" json:"rpm,omitempty"`
+	// MinRpm is the speed (RPM) below which the fan is considered to have failed.
+	MinRpm float64 `protobuf:"fixed64,3,opt,name=min_rpm,json=minRpm,proto3" json:"min_rpm,omitempty"`
+	// Alarm is true if the fan has stopped or is spinning below its minimum speed.
+	Alarm bool `protobuf:"varint,4,opt,name=alarm,proto3" json:"alarm,omitempty"`
+	// PwmPercent is the duty cycle [0-100] that the fan is driven with, if known.
+	PwmPercent float64 `protobuf:"fixed64,5,opt,name=pwm_percent,json=pwmPercent,proto3" json:"pwm_percent,omitempty"`
+}
+
+func (x *FanDeviceMetrics) Reset() {
+	*x = FanDeviceMetrics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_hardware_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FanDeviceMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FanDeviceMetrics) ProtoMessage() {}
+
+func (x *FanDeviceMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hardware_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FanDeviceMetrics.ProtoReflect.Descriptor instead.
+func (*FanDeviceMetrics) Descriptor() ([]byte, []int) {
+	return file_proto_hardware_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FanDeviceMetrics) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *FanDeviceMetrics) GetRpm() float64 {
+	if x != nil {
+		return x.Rpm
+	}
+	return 0
+}
+
+func (x *FanDeviceMetrics) GetMinRpm() float64 {
+	if x != nil {
+		return x.MinRpm
+	}
+	return 0
+}
+
+func (x *FanDeviceMetrics) GetAlarm() bool {
+	if x != nil {
+		return x.Alarm
+	}
+	return false
+}
+
+func (x *FanDeviceMetrics) GetPwmPercent() float64 {
+	if x != nil {
+		return x.PwmPercent
+	}
+	return 0
+}
+
+// VoltageDeviceMetrics holds the voltage and health of a power rail.
+type VoltageDeviceMetrics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Label of the voltage rail, for example "Vcore", "+12V" or "in0" if the rail is not labeled.
+	Label string `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	// Volts is the current voltage of the rail.
+	Volts float64 `protobuf:"fixed64,2,opt,name=volts,proto3" json:"volts,omitempty"`
+	// MinVolts is the lowest voltage that is considered healthy, 0 if not set.
+	MinVolts float64 `protobuf:"fixed64,3,opt,name=min_volts,json=minVolts,proto3" json:"min_volts,omitempty"`
+	// MaxVolts is the highest voltage that is considered healthy, 0 if not set.
+	MaxVolts float64 `protobuf:"fixed64,4,opt,name=max_volts,json=maxVolts,proto3" json:"max_volts,omitempty"`
+	// Alarm is true if the voltage is outside of its limits.
+	Alarm bool `protobuf:"varint,5,opt,name=alarm,proto3" json:"alarm,omitempty"`
+}
+
+func (x *VoltageDeviceMetrics) Reset() {
+	*x = VoltageDeviceMetrics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_hardware_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VoltageDeviceMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoltageDeviceMetrics) ProtoMessage() {}
+
+func (x *VoltageDeviceMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hardware_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoltageDeviceMetrics.ProtoReflect.Descriptor instead.
+func (*VoltageDeviceMetrics) Descriptor() ([]byte, []int) {
+	return file_proto_hardware_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VoltageDeviceMetrics) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *VoltageDeviceMetrics) GetVolts() float64 {
+	if x != nil {
+		return x.Volts
+	}
+	return 0
+}
+
+func (x *VoltageDeviceMetrics) GetMinVolts() float64 {
+	if x != nil {
+		return x.MinVolts
+	}
+	return 0
+}
+
+func (x *VoltageDeviceMetrics) GetMaxVolts() float64 {
+	if x != nil {
+		return x.MaxVolts
+	}
+	return 0
+}
+
+func (x *VoltageDeviceMetrics) GetAlarm() bool {
+	if x != nil {
+		return x.Alarm
+	}
+	return false
+}
+
+// StorageDeviceMetrics holds the temperatures and thresholds of a NVMe or SATA drive.
+type StorageDeviceMetrics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Device is the name of the drive in /dev, for example "nvme0" or "sda".
+	Device string `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	// SensorTemperature is the temperature of each additional sensor of the drive in celcius, like "Sensor 1" and "Sensor 2" of NVMe drives.
+	SensorTemperature []float64 `protobuf:"fixed64,2,rep,packed,name=sensor_temperature,json=sensorTemperature,proto3" json:"sensor_temperature,omitempty"`
+	// TemperatureWarning is the temperature in celcius above which the drive is too hot, 0 if not reported.
+	TemperatureWarning float64 `protobuf:"fixed64,3,opt,name=temperature_warning,json=temperatureWarning,proto3" json:"temperature_warning,omitempty"`
+	// TemperatureCrit is the temperature in celcius above which the drive may be damaged or shut down, 0 if not reported.
+	TemperatureCrit float64 `protobuf:"fixed64,4,opt,name=temperature_crit,json=temperatureCrit,proto3" json:"temperature_crit,omitempty"`
+	// Alarm is true if the drive has reached one of its temperature thresholds.
+	Alarm bool `protobuf:"varint,5,opt,name=alarm,proto3" json:"alarm,omitempty"`
+}
+
+func (x *StorageDeviceMetrics) Reset() {
+	*x = StorageDeviceMetrics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_hardware_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StorageDeviceMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageDeviceMetrics) ProtoMessage() {}
+
+func (x *StorageDeviceMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hardware_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageDeviceMetrics.ProtoReflect.Descriptor instead.
+func (*StorageDeviceMetrics) Descriptor() ([]byte, []int) {
+	return file_proto_hardware_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StorageDeviceMetrics) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *StorageDeviceMetrics) GetSensorTemperature() []float64 {
+	if x != nil {
+		return x.SensorTemperature
+	}
+	return nil
+}
+
+func (x *StorageDeviceMetrics) GetTemperatureWarning() float64 {
+	if x != nil {
+		return x.TemperatureWarning
+	}
+	return 0
+}
+
+func (x *StorageDeviceMetrics) GetTemperatureCrit() float64 {
+	if x != nil {
+		return x.TemperatureCrit
+	}
+	return 0
+}
+
+func (x *StorageDeviceMetrics) GetAlarm() bool {
+	if x != nil {
+		return x.Alarm
+	}
+	return false
+}
+
+// SensorReading is a single reading of a sensor chip that is not described by a device specific message.
+type SensorReading struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Label of the sensor, for example "Composite", "SYSTIN" or "temp1" if the sensor is not labeled.
+	Label string `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	// Type of sensor as named by hwmon, for example "temp", "fan", "in", "power", "curr", "energy" or "humidity".
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// Unit of the value, for example "C", "RPM", "V", "W", "A", "J" or "%".
+	Unit string `protobuf:"bytes,3,opt,name=unit,proto3" json:"unit,omitempty"`
+	// Value is the current reading of the sensor.
+	Value float64 `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	// Min is the low limit of the sensor, 0 if not set.
+	Min float64 `protobuf:"fixed64,5,opt,name=min,proto3" json:"min,omitempty"`
+	// Max is the high limit of the sensor, 0 if not set.
+	Max float64 `protobuf:"fixed64,6,opt,name=max,proto3" json:"max,omitempty"`
+	// Crit is the critical limit of the sensor, 0 if not set.
+	Crit float64 `protobuf:"fixed64,7,opt,name=crit,proto3" json:"crit,omitempty"`
+	// Alarm is true if the sensor is outside of its limits.
+	Alarm bool `protobuf:"varint,8,opt,name=alarm,proto3" json:"alarm,omitempty"`
+}
+
+func (x *SensorReading) Reset() {
+	*x = SensorReading{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_hardware_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SensorReading) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SensorReading) ProtoMessage() {}
+
+func (x *SensorReading) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hardware_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SensorReading.ProtoReflect.Descriptor instead.
+func (*SensorReading) Descriptor() ([]byte, []int) {
+	return file_proto_hardware_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SensorReading) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *SensorReading) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SensorReading) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *SensorReading) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *SensorReading) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *SensorReading) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *SensorReading) GetCrit() float64 {
+	if x != nil {
+		return x.Crit
+	}
+	return 0
+}
+
+func (x *SensorReading) GetAlarm() bool {
+	if x != nil {
+		return x.Alarm
+	}
+	return false
+}
+
+// TripPoint is a temperature at which the kernel starts to cool a thermal zone.
+type TripPoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Type of the trip point, for example "passive", "active", "hot" or "critical".
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// Temperature in celcius at which the trip point is reached.
+	Temperature float64 `protobuf:"fixed64,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	// Hysteresis in celcius below the temperature before the trip point is cleared, 0 if not reported.
+	Hysteresis float64 `protobuf:"fixed64,3,opt,name=hysteresis,proto3" json:"hysteresis,omitempty"`
+	// Index of the trip point in sysfs, the N in trip_point_N_temp.
+	Index int32 `protobuf:"varint,4,opt,name=index,proto3" json:"index,omitempty"`
+}
+
+func (x *TripPoint) Reset() {
+	*x = TripPoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_hardware_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TripPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TripPoint) ProtoMessage() {}
+
+func (x *TripPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hardware_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TripPoint.ProtoReflect.Descriptor instead.
+func (*TripPoint) Descriptor() ([]byte, []int) {
+	return file_proto_hardware_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TripPoint) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TripPoint) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *TripPoint) GetHysteresis() float64 {
+	if x != nil {
+		return x.Hysteresis
+	}
+	return 0
+}
+
+func (x *TripPoint) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+// ThermalZoneMetrics describes a thermal zone of the Linux thermal framework, in /sys/class/thermal.
+type ThermalZoneMetrics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Zone is the name of the zone in sysfs, for example "thermal_zone0".
+	Zone string `protobuf:"bytes,1,opt,name=zone,proto3" json:"zone,omitempty"`
+	// Type of the zone, for example "x86_pkg_temp", "cpu-thermal" or "acpitz".
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// Policy is the thermal governor of the zone, for example "step_wise".
+	Policy string `protobuf:"bytes,3,opt,name=policy,proto3" json:"policy,omitempty"`
+	// TripPoint is every trip point of the zone, in the order of the kernel.
+	TripPoint []*TripPoint `protobuf:"bytes,4,rep,name=trip_point,json=tripPoint,proto3" json:"trip_point,omitempty"`
+}
+
+func (x *ThermalZoneMetrics) Reset() {
+	*x = ThermalZoneMetrics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_hardware_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ThermalZoneMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThermalZoneMetrics) ProtoMessage() {}
+
+func (x *ThermalZoneMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hardware_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThermalZoneMetrics.ProtoReflect.Descriptor instead.
+func (*ThermalZoneMetrics) Descriptor() ([]byte, []int) {
+	return file_proto_hardware_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ThermalZoneMetrics) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *ThermalZoneMetrics) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ThermalZoneMetrics) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *ThermalZoneMetrics) GetTripPoint() []*TripPoint {
+	if x != nil {
+		return x.TripPoint
+	}
+	return nil
+}
+
+// CoolingDeviceMetrics describes a cooling device of the Linux thermal framework, in /sys/class/thermal.
+type CoolingDeviceMetrics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Device is the name of the cooling device in sysfs, for example "cooling_device0".
+	Device string `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	// Type of the cooling device, for example "Processor", "pwm-fan" or "thermal-cpufreq-0".
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// CurState is the current cooling state, 0 is no cooling.
+	CurState int64 `protobuf:"varint,3,opt,name=cur_state,json=curState,proto3" json:"cur_state,omitempty"`
+	// MaxState is the highest cooling state.
+	MaxState int64 `protobuf:"varint,4,opt,name=max_state,json=maxState,proto3" json:"max_state,omitempty"`
+}
+
+func (x *CoolingDeviceMetrics) Reset() {
+	*x = CoolingDeviceMetrics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_hardware_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CoolingDeviceMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoolingDeviceMetrics) ProtoMessage() {}
+
+func (x *CoolingDeviceMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hardware_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoolingDeviceMetrics.ProtoReflect.Descriptor instead.
+func (*CoolingDeviceMetrics) Descriptor() ([]byte, []int) {
+	return file_proto_hardware_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CoolingDeviceMetrics) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *CoolingDeviceMetrics) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CoolingDeviceMetrics) GetCurState() int64 {
+	if x != nil {
+		return x.CurState
+	}
+	return 0
+}
+
+func (x *CoolingDeviceMetrics) GetMaxState() int64 {
+	if x != nil {
+		return x.MaxState
+	}
+	return 0
+}
+
+// ThrottledMetrics is the throttled bitmask of the Raspberry Pi firmware, from "vcgencmd get_throttled".
+// See https://www.raspberrypi.com/documentation/computers/os.html#get_throttled
+type ThrottledMetrics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Flags is the raw bitmask reported by the firmware.
+	Flags uint32 `protobuf:"varint,1,opt,name=flags,proto3" json:"flags,omitempty"`
+	// UnderVoltage is true if the supply voltage is too low right now.
+	UnderVoltage bool `protobuf:"varint,2,opt,name=under_voltage,json=underVoltage,proto3" json:"under_voltage,omitempty"`
+	// FrequencyCapped is true if the ARM frequency is capped right now.
+	FrequencyCapped bool `protobuf:"varint,3,opt,name=frequency_capped,json=frequencyCapped,proto3" json:"frequency_capped,omitempty"`
+	// Throttled is true if the CPU is throttled right now.
+	Throttled bool `protobuf:"varint,4,opt,name=throttled,proto3" json:"throttled,omitempty"`
+	// SoftTemperatureLimit is true if the soft temperature limit is active right now.
+	SoftTemperatureLimit bool `protobuf:"varint,5,opt,name=soft_temperature_limit,json=softTemperatureLimit,proto3" json:"soft_temperature_limit,omitempty"`
+	// UnderVoltageOccurred is true if the supply voltage has been too low since boot.
+	UnderVoltageOccurred bool `protobuf:"varint,6,opt,name=under_voltage_occurred,json=underVoltageOccurred,proto3" json:"under_voltage_occurred,omitempty"`
+	// FrequencyCappedOccurred is true if the ARM frequency has been capped since boot.
+	FrequencyCappedOccurred bool `protobuf:"varint,7,opt,name=frequency_capped_occurred,json=frequencyCappedOccurred,proto3" json:"frequency_capped_occurred,omitempty"`
+	// ThrottledOccurred is true if the CPU has been throttled since boot.
+	ThrottledOccurred bool `protobuf:"varint,8,opt,name=throttled_occurred,json=throttledOccurred,proto3" json:"throttled_occurred,omitempty"`
+	// SoftTemperatureLimitOccurred is true if the soft temperature limit has been reached since boot.
+	SoftTemperatureLimitOccurred bool `protobuf:"varint,9,opt,name=soft_temperature_limit_occurred,json=softTemperatureLimitOccurred,proto3" json:"soft_temperature_limit_occurred,omitempty"`
+}
+
+func (x *ThrottledMetrics) Reset() {
+	*x = ThrottledMetrics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_hardware_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ThrottledMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThrottledMetrics) ProtoMessage() {}
+
+func (x *ThrottledMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hardware_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThrottledMetrics.ProtoReflect.Descriptor instead.
+func (*ThrottledMetrics) Descriptor() ([]byte, []int) {
+	return file_proto_hardware_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ThrottledMetrics) GetFlags() uint32 {
+	if x != nil {
+		return x.Flags
+	}
+	return 0
+}
+
+func (x *ThrottledMetrics) GetUnderVoltage() bool {
+	if x != nil {
+		return x.UnderVoltage
+	}
+	return false
+}
+
+func (x *ThrottledMetrics) GetFrequencyCapped() bool {
+	if x != nil {
+		return x.FrequencyCapped
+	}
+	return false
+}
+
+func (x *ThrottledMetrics) GetThrottled() bool {
+	if x != nil {
+		return x.Throttled
+	}
+	return false
+}
+
+func (x *ThrottledMetrics) GetSoftTemperatureLimit() bool {
+	if x != nil {
+		return x.SoftTemperatureLimit
+	}
+	return false
+}
+
+func (x *ThrottledMetrics) GetUnderVoltageOccurred() bool {
+	if x != nil {
+		return x.UnderVoltageOccurred
+	}
+	return false
+}
+
+func (x *ThrottledMetrics) GetFrequencyCappedOccurred() bool {
+	if x != nil {
+		return x.FrequencyCappedOccurred
+	}
+	return false
+}
+
+func (x *ThrottledMetrics) GetThrottledOccurred() bool {
+	if x != nil {
+		return x.ThrottledOccurred
+	}
+	return false
+}
+
+func (x *ThrottledMetrics) GetSoftTemperatureLimitOccurred() bool {
+	if x != nil {
+		return x.SoftTemperatureLimitOccurred
+	}
+	return false
+}
+
 // DeviceMetrics holds the health metrics (temperature and other measurements) of the device.
 type DeviceMetrics struct {
 	state         protoimpl.MessageState
@@ -134,12 +1049,28 @@ type DeviceMetrics struct {
 	Temperature float64 `protobuf:"fixed64,3,opt,name=temperature,proto3" json:"temperature,omitempty"`
 	// CPU is populated if the device is a CPU.
 	Cpu *CpuDeviceMetrics `protobuf:"bytes,4,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	// Fan is populated if the device is a fan.
+	Fan *FanDeviceMetrics `protobuf:"bytes,5,opt,name=fan,proto3" json:"fan,omitempty"`
+	// Voltage is populated if the device is a voltage rail.
+	Voltage *VoltageDeviceMetrics `protobuf:"bytes,6,opt,name=voltage,proto3" json:"voltage,omitempty"`
+	// Storage is populated if the device is a NVMe or SATA drive.
+	Storage *StorageDeviceMetrics `protobuf:"bytes,7,opt,name=storage,proto3" json:"storage,omitempty"`
+	// Sensor is every reading of the device that is not described by one of the device specific messages.
+	Sensor []*SensorReading `protobuf:"bytes,8,rep,name=sensor,proto3" json:"sensor,omitempty"`
+	// Chip is the name of the sensor chip that the device was read from, for example "nct6775-isa-0290".
+	Chip string `protobuf:"bytes,9,opt,name=chip,proto3" json:"chip,omitempty"`
+	// ThermalZone is populated if the device is a Linux thermal zone.
+	ThermalZone *ThermalZoneMetrics `protobuf:"bytes,10,opt,name=thermal_zone,json=thermalZone,proto3" json:"thermal_zone,omitempty"`
+	// CoolingDevice is populated if the device is a Linux cooling device.
+	CoolingDevice *CoolingDeviceMetrics `protobuf:"bytes,11,opt,name=cooling_device,json=coolingDevice,proto3" json:"cooling_device,omitempty"`
+	// Throttled is populated if the firmware reports why the device was slowed down, like on a Raspberry Pi.
+	Throttled *ThrottledMetrics `protobuf:"bytes,12,opt,name=throttled,proto3" json:"throttled,omitempty"`
 }
 
 func (x *DeviceMetrics) Reset() {
 	*x = DeviceMetrics{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_hardware_proto_msgTypes[1]
+		mi := &file_proto_hardware_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -152,7 +1083,7 @@ func (x *DeviceMetrics) String() string {
 func (*DeviceMetrics) ProtoMessage() {}
 
 func (x *DeviceMetrics) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_hardware_proto_msgTypes[1]
+	mi := &file_proto_hardware_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -165,7 +1096,7 @@ func (x *DeviceMetrics) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeviceMetrics.ProtoReflect.Descriptor instead.
 func (*DeviceMetrics) Descriptor() ([]byte, []int) {
-	return file_proto_hardware_proto_rawDescGZIP(), []int{1}
+	return file_proto_hardware_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *DeviceMetrics) GetName() string {
@@ -196,6 +1127,62 @@ func (x *DeviceMetrics) GetCpu() *CpuDeviceMetrics {
 	return nil
 }
 
+func (x *DeviceMetrics) GetFan() *FanDeviceMetrics {
+	if x != nil {
+		return x.Fan
+	}
+	return nil
+}
+
+func (x *DeviceMetrics) GetVoltage() *VoltageDeviceMetrics {
+	if x != nil {
+		return x.Voltage
+	}
+	return nil
+}
+
+func (x *DeviceMetrics) GetStorage() *StorageDeviceMetrics {
+	if x != nil {
+		return x.Storage
+	}
+	return nil
+}
+
+func (x *DeviceMetrics) GetSensor() []*SensorReading {
+	if x != nil {
+		return x.Sensor
+	}
+	return nil
+}
+
+func (x *DeviceMetrics) GetChip() string {
+	if x != nil {
+		return x.Chip
+	}
+	return ""
+}
+
+func (x *DeviceMetrics) GetThermalZone() *ThermalZoneMetrics {
+	if x != nil {
+		return x.ThermalZone
+	}
+	return nil
+}
+
+func (x *DeviceMetrics) GetCoolingDevice() *CoolingDeviceMetrics {
+	if x != nil {
+		return x.CoolingDevice
+	}
+	return nil
+}
+
+func (x *DeviceMetrics) GetThrottled() *ThrottledMetrics {
+	if x != nil {
+		return x.Throttled
+	}
+	return nil
+}
+
 // MachineMetrics holds a list of devices that can be instrumented for health.
 type MachineMetrics struct {
 	state         protoimpl.MessageState
@@ -213,7 +1200,7 @@ type MachineMetrics struct {
 func (x *MachineMetrics) Reset() {
 	*x = MachineMetrics{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_hardware_proto_msgTypes[2]
+		mi := &file_proto_hardware_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -226,7 +1213,7 @@ func (x *MachineMetrics) String() string {
 func (*MachineMetrics) ProtoMessage() {}
 
 func (x *MachineMetrics) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_hardware_proto_msgTypes[2]
+	mi := &file_proto_hardware_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -239,7 +1226,7 @@ func (x *MachineMetrics) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MachineMetrics.ProtoReflect.Descriptor instead.
 func (*MachineMetrics) Descriptor() ([]byte, []int) {
-	return file_proto_hardware_proto_rawDescGZIP(), []int{2}
+	return file_proto_hardware_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *MachineMetrics) GetName() string {
@@ -271,7 +1258,7 @@ var file_proto_hardware_proto_rawDesc = []byte{
 	0x2e, 0x63, 0x6f, 0x72, 0x65, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74,
 	0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
 	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb6, 0x01, 0x0a, 0x10, 0x43, 0x70,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb3, 0x08, 0x0a, 0x10, 0x43, 0x70,
 	0x75, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x12,
 	0x0a, 0x04, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x52, 0x04, 0x6c, 0x6f,
 	0x61, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72,
@@ -283,31 +1270,219 @@ var file_proto_hardware_proto_rawDesc = []byte{
 	0x6e, 0x63, 0x79, 0x4d, 0x68, 0x7a, 0x12, 0x2a, 0x0a, 0x11, 0x66, 0x73, 0x62, 0x5f, 0x66, 0x72,
 	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x68, 0x7a, 0x18, 0x05, 0x20, 0x01, 0x28,
 	0x01, 0x52, 0x0f, 0x66, 0x73, 0x62, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x4d,
-	0x68, 0x7a, 0x22, 0x9f, 0x01, 0x0a, 0x0d, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x65, 0x74,
-	0x72, 0x69, 0x63, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x20, 0x0a, 0x0b,
-	0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x01, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x44,
-	0x0a, 0x03, 0x63, 0x70, 0x75, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x6a, 0x65,
-	0x72, 0x65, 0x6d, 0x79, 0x6a, 0x65, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x74, 0x65, 0x6d, 0x70, 0x5f,
-	0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43,
-	0x70, 0x75, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52,
-	0x03, 0x63, 0x70, 0x75, 0x22, 0xa7, 0x01, 0x0a, 0x0e, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65,
-	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x47, 0x0a, 0x06, 0x64,
-	0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x6a, 0x65,
+	0x68, 0x7a, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x01, 0x52, 0x08, 0x6c, 0x6f, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x07,
+	0x20, 0x03, 0x28, 0x01, 0x52, 0x0a, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x69, 0x6f, 0x77, 0x61, 0x69, 0x74, 0x18,
+	0x08, 0x20, 0x03, 0x28, 0x01, 0x52, 0x0a, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x6f, 0x77, 0x61, 0x69,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x73, 0x74, 0x65, 0x61, 0x6c, 0x18,
+	0x09, 0x20, 0x03, 0x28, 0x01, 0x52, 0x09, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x74, 0x65, 0x61, 0x6c,
+	0x12, 0x2a, 0x0a, 0x11, 0x63, 0x6f, 0x72, 0x65, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e,
+	0x63, 0x79, 0x5f, 0x68, 0x7a, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x01, 0x52, 0x0f, 0x63, 0x6f, 0x72,
+	0x65, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x48, 0x7a, 0x12, 0x31, 0x0a, 0x15,
+	0x63, 0x6f, 0x72, 0x65, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6d,
+	0x69, 0x6e, 0x5f, 0x68, 0x7a, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x01, 0x52, 0x12, 0x63, 0x6f, 0x72,
+	0x65, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x4d, 0x69, 0x6e, 0x48, 0x7a, 0x12,
+	0x31, 0x0a, 0x15, 0x63, 0x6f, 0x72, 0x65, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x79, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x68, 0x7a, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x01, 0x52, 0x12,
+	0x63, 0x6f, 0x72, 0x65, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x4d, 0x61, 0x78,
+	0x48, 0x7a, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x06, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x2f, 0x0a, 0x13, 0x70, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
+	0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x63,
+	0x63, 0x64, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x0f,
+	0x20, 0x03, 0x28, 0x01, 0x52, 0x0e, 0x63, 0x63, 0x64, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x10, 0x20, 0x03, 0x28, 0x01, 0x52, 0x0e, 0x74,
+	0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x4d, 0x61, 0x78, 0x12, 0x29, 0x0a,
+	0x10, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x63, 0x72, 0x69,
+	0x74, 0x18, 0x11, 0x20, 0x03, 0x28, 0x01, 0x52, 0x0f, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x43, 0x72, 0x69, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x74, 0x65, 0x6d, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x61, 0x6c, 0x61, 0x72, 0x6d, 0x18, 0x12, 0x20,
+	0x03, 0x28, 0x08, 0x52, 0x10, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x41, 0x6c, 0x61, 0x72, 0x6d, 0x12, 0x2e, 0x0a, 0x13, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
+	0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x77, 0x61, 0x74, 0x74, 0x73, 0x18, 0x13, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x11, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x50, 0x6f, 0x77, 0x65, 0x72,
+	0x57, 0x61, 0x74, 0x74, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x63, 0x6f, 0x72, 0x65, 0x5f, 0x70, 0x6f,
+	0x77, 0x65, 0x72, 0x5f, 0x77, 0x61, 0x74, 0x74, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0e, 0x63, 0x6f, 0x72, 0x65, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x57, 0x61, 0x74, 0x74, 0x73, 0x12,
+	0x28, 0x0a, 0x10, 0x64, 0x72, 0x61, 0x6d, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x77, 0x61,
+	0x74, 0x74, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x64, 0x72, 0x61, 0x6d, 0x50,
+	0x6f, 0x77, 0x65, 0x72, 0x57, 0x61, 0x74, 0x74, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x70, 0x61, 0x63,
+	0x6b, 0x61, 0x67, 0x65, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x6a, 0x6f, 0x75, 0x6c,
+	0x65, 0x73, 0x18, 0x16, 0x20, 0x01, 0x28, 0x01, 0x52, 0x13, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67,
+	0x65, 0x45, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x4a, 0x6f, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x2c, 0x0a,
+	0x12, 0x63, 0x6f, 0x72, 0x65, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x6a, 0x6f, 0x75,
+	0x6c, 0x65, 0x73, 0x18, 0x17, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x63, 0x6f, 0x72, 0x65, 0x45,
+	0x6e, 0x65, 0x72, 0x67, 0x79, 0x4a, 0x6f, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x64,
+	0x72, 0x61, 0x6d, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x6a, 0x6f, 0x75, 0x6c, 0x65,
+	0x73, 0x18, 0x18, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x64, 0x72, 0x61, 0x6d, 0x45, 0x6e, 0x65,
+	0x72, 0x67, 0x79, 0x4a, 0x6f, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x64, 0x70,
+	0x5f, 0x77, 0x61, 0x74, 0x74, 0x73, 0x18, 0x19, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x74, 0x64,
+	0x70, 0x57, 0x61, 0x74, 0x74, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70,
+	0x6c, 0x69, 0x65, 0x72, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6d, 0x75, 0x6c, 0x74,
+	0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x72, 0x65, 0x5f, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x1b, 0x20, 0x03, 0x28, 0x01, 0x52,
+	0x0e, 0x63, 0x6f, 0x72, 0x65, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x22,
+	0x8a, 0x01, 0x0a, 0x10, 0x46, 0x61, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x70,
+	0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x72, 0x70, 0x6d, 0x12, 0x17, 0x0a, 0x07,
+	0x6d, 0x69, 0x6e, 0x5f, 0x72, 0x70, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6d,
+	0x69, 0x6e, 0x52, 0x70, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x61, 0x72, 0x6d, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61, 0x6c, 0x61, 0x72, 0x6d, 0x12, 0x1f, 0x0a, 0x0b, 0x70,
+	0x77, 0x6d, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0a, 0x70, 0x77, 0x6d, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x22, 0x92, 0x01, 0x0a,
+	0x14, 0x56, 0x6f, 0x6c, 0x74, 0x61, 0x67, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x6f, 0x6c, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x6f, 0x6c, 0x74,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x69, 0x6e, 0x5f, 0x76, 0x6f, 0x6c, 0x74, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x56, 0x6f, 0x6c, 0x74, 0x73, 0x12, 0x1b,
+	0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x76, 0x6f, 0x6c, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x56, 0x6f, 0x6c, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x61,
+	0x6c, 0x61, 0x72, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61, 0x6c, 0x61, 0x72,
+	0x6d, 0x22, 0xcf, 0x01, 0x0a, 0x14, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x2d, 0x0a, 0x12, 0x73, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x5f, 0x74, 0x65, 0x6d,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x01, 0x52, 0x11,
+	0x73, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x12, 0x2f, 0x0a, 0x13, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x5f, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12,
+	0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x57, 0x61, 0x72, 0x6e, 0x69,
+	0x6e, 0x67, 0x12, 0x29, 0x0a, 0x10, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x5f, 0x63, 0x72, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x74, 0x65,
+	0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x43, 0x72, 0x69, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x61, 0x6c, 0x61, 0x72, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61, 0x6c,
+	0x61, 0x72, 0x6d, 0x22, 0xb1, 0x01, 0x0a, 0x0d, 0x53, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x52, 0x65,
+	0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75,
+	0x6e, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d,
+	0x61, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x72, 0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x63, 0x72, 0x69,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x61, 0x72, 0x6d, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x61, 0x6c, 0x61, 0x72, 0x6d, 0x22, 0x77, 0x0a, 0x09, 0x54, 0x72, 0x69, 0x70, 0x50,
+	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x74,
+	0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x68, 0x79,
+	0x73, 0x74, 0x65, 0x72, 0x65, 0x73, 0x69, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a,
+	0x68, 0x79, 0x73, 0x74, 0x65, 0x72, 0x65, 0x73, 0x69, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x22, 0xa0, 0x01, 0x0a, 0x12, 0x54, 0x68, 0x65, 0x72, 0x6d, 0x61, 0x6c, 0x5a, 0x6f, 0x6e, 0x65,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x7a, 0x6f, 0x6e, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x4a, 0x0a, 0x0a, 0x74, 0x72, 0x69, 0x70, 0x5f,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x6a, 0x65,
 	0x72, 0x65, 0x6d, 0x79, 0x6a, 0x65, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x74, 0x65, 0x6d, 0x70, 0x5f,
-	0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44,
-	0x65, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x06, 0x64, 0x65,
-	0x76, 0x69, 0x63, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x2d,
-	0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x65, 0x72,
-	0x65, 0x6d, 0x79, 0x6a, 0x65, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x74, 0x65, 0x6d, 0x70, 0x2d, 0x65,
-	0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54,
+	0x72, 0x69, 0x70, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x09, 0x74, 0x72, 0x69, 0x70, 0x50, 0x6f,
+	0x69, 0x6e, 0x74, 0x22, 0x7c, 0x0a, 0x14, 0x43, 0x6f, 0x6f, 0x6c, 0x69, 0x6e, 0x67, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x75, 0x72, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x63, 0x75, 0x72, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x22, 0xb4, 0x03, 0x0a, 0x10, 0x54, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x4d,
+	0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x23, 0x0a, 0x0d,
+	0x75, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x76, 0x6f, 0x6c, 0x74, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0c, 0x75, 0x6e, 0x64, 0x65, 0x72, 0x56, 0x6f, 0x6c, 0x74, 0x61, 0x67,
+	0x65, 0x12, 0x29, 0x0a, 0x10, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x63,
+	0x61, 0x70, 0x70, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x66, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x43, 0x61, 0x70, 0x70, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x09, 0x74, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x16, 0x73, 0x6f,
+	0x66, 0x74, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x73, 0x6f, 0x66, 0x74,
+	0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x34, 0x0a, 0x16, 0x75, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x76, 0x6f, 0x6c, 0x74, 0x61, 0x67,
+	0x65, 0x5f, 0x6f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x14, 0x75, 0x6e, 0x64, 0x65, 0x72, 0x56, 0x6f, 0x6c, 0x74, 0x61, 0x67, 0x65, 0x4f, 0x63,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x64, 0x12, 0x3a, 0x0a, 0x19, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x79, 0x5f, 0x63, 0x61, 0x70, 0x70, 0x65, 0x64, 0x5f, 0x6f, 0x63, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x17, 0x66, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x79, 0x43, 0x61, 0x70, 0x70, 0x65, 0x64, 0x4f, 0x63, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x64, 0x12, 0x2d, 0x0a, 0x12, 0x74, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x5f,
+	0x6f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11,
+	0x74, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x4f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x64, 0x12, 0x45, 0x0a, 0x1f, 0x73, 0x6f, 0x66, 0x74, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x6f, 0x63, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1c, 0x73, 0x6f, 0x66, 0x74,
+	0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x4f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65, 0x64, 0x22, 0xf0, 0x05, 0x0a, 0x0d, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69,
+	0x6e, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x12, 0x44, 0x0a, 0x03, 0x63, 0x70, 0x75, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x32, 0x2e, 0x6a, 0x65, 0x72, 0x65, 0x6d, 0x79, 0x6a, 0x65, 0x2e, 0x63, 0x6f, 0x72,
+	0x65, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x70, 0x75, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x03, 0x63, 0x70, 0x75, 0x12, 0x44, 0x0a, 0x03, 0x66, 0x61,
+	0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x6a, 0x65, 0x72, 0x65, 0x6d, 0x79,
+	0x6a, 0x65, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x65, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x61, 0x6e, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x03, 0x66, 0x61, 0x6e,
+	0x12, 0x50, 0x0a, 0x07, 0x76, 0x6f, 0x6c, 0x74, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x36, 0x2e, 0x6a, 0x65, 0x72, 0x65, 0x6d, 0x79, 0x6a, 0x65, 0x2e, 0x63, 0x6f, 0x72,
+	0x65, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x56, 0x6f, 0x6c, 0x74, 0x61, 0x67, 0x65, 0x44, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x07, 0x76, 0x6f, 0x6c, 0x74, 0x61,
+	0x67, 0x65, 0x12, 0x50, 0x0a, 0x07, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x36, 0x2e, 0x6a, 0x65, 0x72, 0x65, 0x6d, 0x79, 0x6a, 0x65, 0x2e, 0x63,
+	0x6f, 0x72, 0x65, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x07, 0x73, 0x74, 0x6f,
+	0x72, 0x61, 0x67, 0x65, 0x12, 0x47, 0x0a, 0x06, 0x73, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x6a, 0x65, 0x72, 0x65, 0x6d, 0x79, 0x6a, 0x65, 0x2e,
+	0x63, 0x6f, 0x72, 0x65, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65,
+	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x52, 0x65,
+	0x61, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x06, 0x73, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x68, 0x69, 0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x68, 0x69,
+	0x70, 0x12, 0x57, 0x0a, 0x0c, 0x74, 0x68, 0x65, 0x72, 0x6d, 0x61, 0x6c, 0x5f, 0x7a, 0x6f, 0x6e,
+	0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x6a, 0x65, 0x72, 0x65, 0x6d, 0x79,
+	0x6a, 0x65, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x65, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x68, 0x65, 0x72, 0x6d,
+	0x61, 0x6c, 0x5a, 0x6f, 0x6e, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x0b, 0x74,
+	0x68, 0x65, 0x72, 0x6d, 0x61, 0x6c, 0x5a, 0x6f, 0x6e, 0x65, 0x12, 0x5d, 0x0a, 0x0e, 0x63, 0x6f,
+	0x6f, 0x6c, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x36, 0x2e, 0x6a, 0x65, 0x72, 0x65, 0x6d, 0x79, 0x6a, 0x65, 0x2e, 0x63, 0x6f,
+	0x72, 0x65, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6f, 0x6c, 0x69, 0x6e, 0x67, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x0d, 0x63, 0x6f, 0x6f, 0x6c,
+	0x69, 0x6e, 0x67, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x50, 0x0a, 0x09, 0x74, 0x68, 0x72,
+	0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x6a,
+	0x65, 0x72, 0x65, 0x6d, 0x79, 0x6a, 0x65, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x74, 0x65, 0x6d, 0x70,
+	0x5f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x54, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
+	0x52, 0x09, 0x74, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x22, 0xa7, 0x01, 0x0a, 0x0e,
+	0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x47, 0x0a, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x6a, 0x65, 0x72, 0x65, 0x6d, 0x79, 0x6a, 0x65, 0x2e, 0x63, 0x6f,
+	0x72, 0x65, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x73, 0x52, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x65, 0x72, 0x65, 0x6d, 0x79, 0x6a, 0x65, 0x2f, 0x63, 0x6f, 0x72,
+	0x65, 0x74, 0x65, 0x6d, 0x70, 0x2d, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x72, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -322,22 +1497,38 @@ func file_proto_hardware_proto_rawDescGZIP() []byte {
 	return file_proto_hardware_proto_rawDescData
 }
 
-var file_proto_hardware_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_hardware_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_proto_hardware_proto_goTypes = []interface{}{
 	(*CpuDeviceMetrics)(nil),      // 0: jeremyje.coretemp_exporter.proto.CpuDeviceMetrics
-	(*DeviceMetrics)(nil),         // 1: jeremyje.coretemp_exporter.proto.DeviceMetrics
-	(*MachineMetrics)(nil),        // 2: jeremyje.coretemp_exporter.proto.MachineMetrics
-	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+	(*FanDeviceMetrics)(nil),      // 1: jeremyje.coretemp_exporter.proto.FanDeviceMetrics
+	(*VoltageDeviceMetrics)(nil),  // 2: jeremyje.coretemp_exporter.proto.VoltageDeviceMetrics
+	(*StorageDeviceMetrics)(nil),  // 3: jeremyje.coretemp_exporter.proto.StorageDeviceMetrics
+	(*SensorReading)(nil),         // 4: jeremyje.coretemp_exporter.proto.SensorReading
+	(*TripPoint)(nil),             // 5: jeremyje.coretemp_exporter.proto.TripPoint
+	(*ThermalZoneMetrics)(nil),    // 6: jeremyje.coretemp_exporter.proto.ThermalZoneMetrics
+	(*CoolingDeviceMetrics)(nil),  // 7: jeremyje.coretemp_exporter.proto.CoolingDeviceMetrics
+	(*ThrottledMetrics)(nil),      // 8: jeremyje.coretemp_exporter.proto.ThrottledMetrics
+	(*DeviceMetrics)(nil),         // 9: jeremyje.coretemp_exporter.proto.DeviceMetrics
+	(*MachineMetrics)(nil),        // 10: jeremyje.coretemp_exporter.proto.MachineMetrics
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
 }
 var file_proto_hardware_proto_depIdxs = []int32{
-	0, // 0: jeremyje.coretemp_exporter.proto.DeviceMetrics.cpu:type_name -> jeremyje.coretemp_exporter.proto.CpuDeviceMetrics
-	1, // 1: jeremyje.coretemp_exporter.proto.MachineMetrics.device:type_name -> jeremyje.coretemp_exporter.proto.DeviceMetrics
-	3, // 2: jeremyje.coretemp_exporter.proto.MachineMetrics.timestamp:type_name -> google.protobuf.Timestamp
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	5,  // 0: jeremyje.coretemp_exporter.proto.ThermalZoneMetrics.trip_point:type_name -> jeremyje.coretemp_exporter.proto.TripPoint
+	0,  // 1: jeremyje.coretemp_exporter.proto.DeviceMetrics.cpu:type_name -> jeremyje.coretemp_exporter.proto.CpuDeviceMetrics
+	1,  // 2: jeremyje.coretemp_exporter.proto.DeviceMetrics.fan:type_name -> jeremyje.coretemp_exporter.proto.FanDeviceMetrics
+	2,  // 3: jeremyje.coretemp_exporter.proto.DeviceMetrics.voltage:type_name -> jeremyje.coretemp_exporter.proto.VoltageDeviceMetrics
+	3,  // 4: jeremyje.coretemp_exporter.proto.DeviceMetrics.storage:type_name -> jeremyje.coretemp_exporter.proto.StorageDeviceMetrics
+	4,  // 5: jeremyje.coretemp_exporter.proto.DeviceMetrics.sensor:type_name -> jeremyje.coretemp_exporter.proto.SensorReading
+	6,  // 6: jeremyje.coretemp_exporter.proto.DeviceMetrics.thermal_zone:type_name -> jeremyje.coretemp_exporter.proto.ThermalZoneMetrics
+	7,  // 7: jeremyje.coretemp_exporter.proto.DeviceMetrics.cooling_device:type_name -> jeremyje.coretemp_exporter.proto.CoolingDeviceMetrics
+	8,  // 8: jeremyje.coretemp_exporter.proto.DeviceMetrics.throttled:type_name -> jeremyje.coretemp_exporter.proto.ThrottledMetrics
+	9,  // 9: jeremyje.coretemp_exporter.proto.MachineMetrics.device:type_name -> jeremyje.coretemp_exporter.proto.DeviceMetrics
+	11, // 10: jeremyje.coretemp_exporter.proto.MachineMetrics.timestamp:type_name -> google.protobuf.Timestamp
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_proto_hardware_proto_init() }
@@ -359,7 +1550,7 @@ func file_proto_hardware_proto_init() {
 			}
 		}
 		file_proto_hardware_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeviceMetrics); i {
+			switch v := v.(*FanDeviceMetrics); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -371,6 +1562,102 @@ func file_proto_hardware_proto_init() {
 			}
 		}
 		file_proto_hardware_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VoltageDeviceMetrics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_hardware_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StorageDeviceMetrics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_hardware_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SensorReading); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_hardware_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TripPoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_hardware_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ThermalZoneMetrics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_hardware_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CoolingDeviceMetrics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_hardware_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ThrottledMetrics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_hardware_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeviceMetrics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_hardware_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*MachineMetrics); i {
 			case 0:
 				return &v.state
@@ -389,7 +1676,7 @@ func file_proto_hardware_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_hardware_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},